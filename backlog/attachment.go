@@ -0,0 +1,98 @@
+package backlog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"time"
+)
+
+// Attachment represents a file attached to a Backlog resource such as a wiki page.
+// Files uploaded to the space have only the ID, name and size until they are attached.
+type Attachment struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	Size        int64     `json:"size"`
+	CreatedUser *User     `json:"createdUser,omitempty"`
+	Created     time.Time `json:"created,omitzero"`
+}
+
+// UploadAttachment uploads a file to the space so that it can be attached to a resource.
+// The content is read from the reader that open returns, which is called again if the request is retried.
+func (c *Client) UploadAttachment(name string, open func() (io.ReadCloser, error)) (*Attachment, error) {
+	return c.UploadAttachmentContext(context.Background(), name, open)
+}
+
+// UploadAttachmentContext is like UploadAttachment but uses the specified context for the request.
+func (c *Client) UploadAttachmentContext(ctx context.Context, name string, open func() (io.ReadCloser, error)) (*Attachment, error) {
+	req, err := c.NewRequest(ctx, http.MethodPost, "/api/v2/space/attachment", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// The body is streamed through a pipe so that large files are not held in memory.
+	// Every body uses the same boundary because the header is set only once.
+	boundary := multipart.NewWriter(io.Discard).Boundary()
+	body := func() (io.ReadCloser, error) {
+		r, err := open()
+		if err != nil {
+			return nil, err
+		}
+		pr, pw := io.Pipe()
+		go func() {
+			defer func() { _ = r.Close() }()
+			mw := multipart.NewWriter(pw)
+			err := mw.SetBoundary(boundary)
+			if err == nil {
+				var part io.Writer
+				part, err = mw.CreateFormFile("file", name)
+				if err == nil {
+					_, err = io.Copy(part, r)
+				}
+			}
+			if err == nil {
+				err = mw.Close()
+			}
+			pw.CloseWithError(err)
+		}()
+		return pr, nil
+	}
+	req.Body, err = body()
+	if err != nil {
+		return nil, err
+	}
+	req.GetBody = body
+	req.Header.Set("Content-Type", "multipart/form-data; boundary="+boundary)
+
+	a := &Attachment{}
+	if err := c.Do(req, a); err != nil {
+		return nil, fmt.Errorf("failed to upload attachment: %w", err)
+	}
+
+	return a, nil
+}
+
+// Download sends a GET request to the path and copies the response body to w as it is received.
+// It returns the number of bytes written. A response with a non-2xx status code is returned as an *APIError.
+func (c *Client) Download(ctx context.Context, path string, w io.Writer) (int64, error) {
+	req, err := c.NewRequest(ctx, http.MethodGet, path, nil, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return 0, redactError(c.authenticator(), err)
+	}
+
+	//nolint:errcheck
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return 0, newAPIError(req, resp)
+	}
+
+	return io.Copy(w, resp.Body)
+}
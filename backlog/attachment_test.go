@@ -0,0 +1,200 @@
+package backlog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_UploadAttachment(t *testing.T) {
+	type mock struct {
+		statuses []int
+	}
+	type expected struct {
+		value   *Attachment
+		opens   int
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		open     func() (io.ReadCloser, error)
+		mock     mock
+		expected expected
+	}{
+		{
+			name: "basic",
+			mock: mock{
+				statuses: []int{200},
+			},
+			expected: expected{
+				value: &Attachment{ID: 1, Name: "design.png", Size: 7},
+				opens: 1,
+			},
+		},
+		{
+			name: "retried",
+			mock: mock{
+				statuses: []int{503, 200},
+			},
+			expected: expected{
+				value: &Attachment{ID: 1, Name: "design.png", Size: 7},
+				opens: 2,
+			},
+		},
+		{
+			name: "api error",
+			mock: mock{
+				statuses: []int{400},
+			},
+			expected: expected{
+				opens:   1,
+				isError: true,
+			},
+		},
+		{
+			name: "open error",
+			open: func() (io.ReadCloser, error) {
+				return nil, errors.New("no such file")
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			c := &Client{
+				BaseURL: "https://example.com",
+				APIKey:  "dummy",
+				HTTPClient: &http.Client{
+					Transport: &RetryableTransport{
+						Transport:        http.DefaultTransport,
+						InitialInterval:  time.Millisecond,
+						MaxInterval:      time.Millisecond,
+						MaxRetryAttempts: 3,
+						MaxJitterMilli:   1,
+					},
+				},
+			}
+			i := 0
+			httpmock.RegisterResponder(
+				http.MethodPost,
+				"https://example.com/api/v2/space/attachment?apiKey=dummy",
+				func(req *http.Request) (*http.Response, error) {
+					status := tt.mock.statuses[i]
+					i++
+					if err := req.ParseMultipartForm(1 << 20); err != nil {
+						return nil, err
+					}
+					f, h, err := req.FormFile("file")
+					if err != nil {
+						return nil, err
+					}
+					b, err := io.ReadAll(f)
+					if err != nil {
+						return nil, err
+					}
+					assert.Equal(t, "design.png", h.Filename)
+					assert.Equal(t, "content", string(b))
+					if status != 200 {
+						return httpmock.NewStringResponse(status, `{"errors":[]}`), nil
+					}
+					return httpmock.NewStringResponse(status, `{"id":1,"name":"design.png","size":7}`), nil
+				},
+			)
+			opens := 0
+			open := tt.open
+			if open == nil {
+				open = func() (io.ReadCloser, error) {
+					opens++
+					return io.NopCloser(strings.NewReader("content")), nil
+				}
+			}
+			actual, err := c.UploadAttachment("design.png", open)
+			assert.Equal(t, tt.expected.opens, opens)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.value, actual)
+		})
+	}
+}
+
+func TestClient_Download(t *testing.T) {
+	type mock struct {
+		status int
+		body   string
+	}
+	type expected struct {
+		value     string
+		isError   bool
+		errStatus int
+	}
+	tests := []struct {
+		name     string
+		mock     mock
+		expected expected
+	}{
+		{
+			name: "basic",
+			mock: mock{
+				status: 200,
+				body:   "\x89PNG\r\n",
+			},
+			expected: expected{
+				value: "\x89PNG\r\n",
+			},
+		},
+		{
+			name: "api error",
+			mock: mock{
+				status: 404,
+				body:   `{"errors":[{"message":"No attachment.","code":6}]}`,
+			},
+			expected: expected{
+				isError:   true,
+				errStatus: 404,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			c := &Client{
+				BaseURL:    "https://example.com",
+				APIKey:     "dummy",
+				HTTPClient: &http.Client{},
+			}
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				"https://example.com/api/v2/wikis/1/attachments/2?apiKey=dummy",
+				httpmock.NewStringResponder(tt.mock.status, tt.mock.body),
+			)
+			buf := &bytes.Buffer{}
+			n, err := c.Download(context.Background(), "/api/v2/wikis/1/attachments/2", buf)
+			if tt.expected.isError {
+				var apiErr *APIError
+				assert.ErrorAs(t, err, &apiErr)
+				assert.Equal(t, tt.expected.errStatus, apiErr.StatusCode)
+				assert.Zero(t, buf.Len())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, int64(len(tt.expected.value)), n)
+			assert.Equal(t, tt.expected.value, buf.String())
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package backlog
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const redacted = "REDACTED"
+
+// Authenticator authenticates Backlog API requests.
+type Authenticator interface {
+	// Authenticate adds the credential to the request.
+	Authenticate(req *http.Request) error
+
+	// Redact returns s with the credential replaced so that it can be logged safely.
+	Redact(s string) string
+}
+
+var (
+	_ Authenticator = APIKey("")
+	_ Authenticator = BearerToken("")
+)
+
+// APIKey authenticates requests with a Backlog API key.
+// Backlog accepts API keys only as the apiKey query parameter, so this is the fallback
+// used when no header-based authenticator is available. Redact keeps the key out of
+// the logs and errors of the client, but the key still reaches the access logs of
+// proxies and servers on the way, so BearerToken should be preferred where possible.
+type APIKey string
+
+// Authenticate appends the API key to the query string of the request.
+func (k APIKey) Authenticate(req *http.Request) error {
+	if k == "" {
+		return errors.New("empty API key")
+	}
+	q := url.Values{"apiKey": {string(k)}}.Encode()
+	if req.URL.RawQuery == "" {
+		req.URL.RawQuery = q
+	} else {
+		req.URL.RawQuery += "&" + q
+	}
+	return nil
+}
+
+// Redact returns s with the API key replaced.
+func (k APIKey) Redact(s string) string {
+	return redact(s, string(k), url.QueryEscape(string(k)))
+}
+
+// BearerToken authenticates requests with an access token in the Authorization header.
+type BearerToken string
+
+// Authenticate sets the Authorization header of the request.
+func (t BearerToken) Authenticate(req *http.Request) error {
+	if t == "" {
+		return errors.New("empty access token")
+	}
+	req.Header.Set("Authorization", "Bearer "+string(t))
+	return nil
+}
+
+// Redact returns s with the access token replaced.
+func (t BearerToken) Redact(s string) string {
+	return redact(s, string(t))
+}
+
+func redact(s string, secrets ...string) string {
+	for _, secret := range secrets {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, redacted)
+		}
+	}
+	return s
+}
+
+// redactedError hides credentials in the message of the wrapped error.
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// redactError returns err with the credential of a replaced in its message.
+// The URL of a wrapped *url.Error is redacted in place.
+func redactError(a Authenticator, err error) error {
+	if err == nil || a == nil {
+		return err
+	}
+	var ue *url.Error
+	if errors.As(err, &ue) {
+		ue.URL = a.Redact(ue.URL)
+	}
+	msg := err.Error()
+	if s := a.Redact(msg); s != msg {
+		return &redactedError{msg: s, err: err}
+	}
+	return err
+}
@@ -0,0 +1,135 @@
+package backlog
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthenticator_Authenticate(t *testing.T) {
+	type args struct {
+		auth Authenticator
+		url  string
+	}
+	type expected struct {
+		url     string
+		header  string
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "api key",
+			args: args{
+				auth: APIKey("dummy"),
+				url:  "https://example.com/api/v2/wikis",
+			},
+			expected: expected{
+				url:     "https://example.com/api/v2/wikis?apiKey=dummy",
+				header:  "",
+				isError: false,
+			},
+		},
+		{
+			name: "api key with query",
+			args: args{
+				auth: APIKey("dummy"),
+				url:  "https://example.com/api/v2/wikis?projectIdOrKey=TEST",
+			},
+			expected: expected{
+				url:     "https://example.com/api/v2/wikis?projectIdOrKey=TEST&apiKey=dummy",
+				header:  "",
+				isError: false,
+			},
+		},
+		{
+			name: "empty api key",
+			args: args{
+				auth: APIKey(""),
+				url:  "https://example.com/api/v2/wikis",
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name: "bearer token",
+			args: args{
+				auth: BearerToken("token"),
+				url:  "https://example.com/api/v2/wikis",
+			},
+			expected: expected{
+				url:     "https://example.com/api/v2/wikis",
+				header:  "Bearer token",
+				isError: false,
+			},
+		},
+		{
+			name: "empty bearer token",
+			args: args{
+				auth: BearerToken(""),
+				url:  "https://example.com/api/v2/wikis",
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, tt.args.url, nil)
+			assert.NoError(t, err)
+			err = tt.args.auth.Authenticate(req)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.url, req.URL.String())
+			assert.Equal(t, tt.expected.header, req.Header.Get("Authorization"))
+		})
+	}
+}
+
+func TestAuthenticator_Redact(t *testing.T) {
+	assert.Equal(t, "?apiKey=REDACTED", APIKey("a+b/c").Redact("?apiKey=a%2Bb%2Fc"))
+	assert.Equal(t, "key: REDACTED", APIKey("a+b/c").Redact("key: a+b/c"))
+	assert.Equal(t, "Bearer REDACTED", BearerToken("token").Redact("Bearer token"))
+	assert.Equal(t, "nothing", APIKey("").Redact("nothing"))
+}
+
+func TestClient_Do_redact(t *testing.T) {
+	c := &Client{
+		BaseURL: "https://example.com",
+		APIKey:  "secret",
+		HTTPClient: &http.Client{
+			Transport: &mockRoundTripper{errors: []error{errors.New("connection refused")}},
+		},
+	}
+	req, err := c.NewRequest(context.Background(), http.MethodGet, "/api/v2/test", nil, nil)
+	assert.NoError(t, err)
+	err = c.Do(req, nil)
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret")
+	assert.Contains(t, err.Error(), "apiKey=REDACTED")
+	var ue *url.Error
+	assert.True(t, errors.As(err, &ue))
+	assert.NotContains(t, ue.URL, "secret")
+	assert.Equal(t, "?apiKey=REDACTED", c.Redact("?apiKey=secret"))
+}
+
+func Test_redactError(t *testing.T) {
+	err := errors.New("failed: secret")
+	actual := redactError(APIKey("secret"), err)
+	assert.Equal(t, "failed: REDACTED", actual.Error())
+	assert.ErrorIs(t, actual, err)
+	assert.Equal(t, err, redactError(APIKey("other"), err))
+	assert.NoError(t, redactError(APIKey("secret"), nil))
+}
@@ -11,11 +11,13 @@ import (
 var nowFunc = time.Now
 
 // Client represents a Backlog client.
+// Requests are authenticated with Auth, or with APIKey in the query string if Auth is nil.
 type Client struct {
-	BaseURL    string       `json:"baseUrl"`
-	APIKey     string       `json:"-"`
-	Writer     io.Writer    `json:"-"`
-	HTTPClient *http.Client `json:"-"`
+	BaseURL    string        `json:"baseUrl"`
+	APIKey     string        `json:"-"`
+	Auth       Authenticator `json:"-"`
+	Writer     io.Writer     `json:"-"`
+	HTTPClient *http.Client  `json:"-"`
 }
 
 // ClientOption represents an option for configuring the Backlog client.
@@ -41,14 +43,19 @@ func WithTransport(transport http.RoundTripper) ClientOption {
 	}
 }
 
+// WithAuthenticator sets the authenticator for the Backlog client.
+// The API key passed to NewClient may be empty if an authenticator is set.
+func WithAuthenticator(auth Authenticator) ClientOption {
+	return func(o *Client) {
+		o.Auth = auth
+	}
+}
+
 // NewClient creates a new Backlog client.
 func NewClient(url, apiKey string, opts ...ClientOption) (*Client, error) {
 	if url == "" {
 		return nil, errors.New("empty URL")
 	}
-	if apiKey == "" {
-		return nil, errors.New("empty API key")
-	}
 	o := &Client{
 		BaseURL:    url,
 		APIKey:     apiKey,
@@ -58,5 +65,20 @@ func NewClient(url, apiKey string, opts ...ClientOption) (*Client, error) {
 	for _, opt := range opts {
 		opt(o)
 	}
+	if o.Auth == nil && apiKey == "" {
+		return nil, errors.New("empty API key")
+	}
 	return o, nil
 }
+
+// Redact returns s with the credential of the client replaced so that it can be logged safely.
+func (c *Client) Redact(s string) string {
+	return c.authenticator().Redact(s)
+}
+
+func (c *Client) authenticator() Authenticator {
+	if c.Auth != nil {
+		return c.Auth
+	}
+	return APIKey(c.APIKey)
+}
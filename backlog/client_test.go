@@ -151,6 +151,25 @@ func TestNewClient(t *testing.T) {
 				isError: true,
 			},
 		},
+		{
+			name: "authenticator",
+			args: args{
+				url:    "https://example.com",
+				apiKey: "",
+				opts: []ClientOption{
+					WithAuthenticator(BearerToken("token")),
+				},
+			},
+			expected: expected{
+				value: &Client{
+					Writer:     os.Stdout,
+					BaseURL:    "https://example.com",
+					Auth:       BearerToken("token"),
+					HTTPClient: &http.Client{},
+				},
+				isError: false,
+			},
+		},
 		{
 			name: "empty api key",
 			args: args{
@@ -2,11 +2,39 @@ package backlog
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"strings"
 )
 
+// Error codes returned by the Backlog API.
+// See https://developer.nulab.com/docs/backlog/error-response/
+const (
+	ErrorCodeInternal              = 1
+	ErrorCodeLicence               = 2
+	ErrorCodeLicenceExpired        = 3
+	ErrorCodeAccessDenied          = 4
+	ErrorCodeUnauthorizedOperation = 5
+	ErrorCodeNoResource            = 6
+	ErrorCodeInvalidRequest        = 7
+	ErrorCodeSpaceOverCapacity     = 8
+	ErrorCodeResourceOverflow      = 9
+	ErrorCodeTooLargeFile          = 10
+	ErrorCodeAuthentication        = 11
+	ErrorCodeRequiredMFA           = 12
+	ErrorCodeTooManyRequests       = 13
+)
+
+// Sentinel errors that an *APIError matches with errors.Is.
+var (
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrRateLimited  = errors.New("rate limited")
+)
+
 // Error represents an error response from the Backlog API.
 type Error struct {
 	Message  string `json:"message"`
@@ -19,7 +47,78 @@ type ErrorResponse struct {
 	Errors []Error `json:"errors"`
 }
 
+// APIError represents a non-2xx response from the Backlog API.
+type APIError struct {
+	StatusCode int        `json:"statusCode"`
+	Method     string     `json:"method"`
+	Path       string     `json:"path"`
+	Errors     []Error    `json:"errors"`
+	RateLimit  *RateLimit `json:"rateLimit,omitempty"`
+}
+
+// Error returns the status code and the error messages of the response.
+func (e *APIError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		messages = append(messages, err.Message)
+	}
+	if len(messages) == 0 {
+		return fmt.Sprintf("%d: %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("%d: %s", e.StatusCode, strings.Join(messages, "; "))
+}
+
+// Is reports whether the error matches one of the sentinel errors of this package.
+// The status code and the Backlog error codes are both taken into account.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.HasCode(ErrorCodeAuthentication)
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden || e.HasCode(ErrorCodeAccessDenied) || e.HasCode(ErrorCodeUnauthorizedOperation)
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound || e.HasCode(ErrorCodeNoResource)
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests || e.HasCode(ErrorCodeTooManyRequests)
+	default:
+		return false
+	}
+}
+
+// HasCode reports whether the response contains an error with the specified code.
+func (e *APIError) HasCode(code int) bool {
+	for _, err := range e.Errors {
+		if err.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+func newAPIError(req *http.Request, resp *http.Response) *APIError {
+	e := &APIError{
+		StatusCode: resp.StatusCode,
+		RateLimit:  ParseRateLimit(resp.Header),
+	}
+	if req != nil {
+		e.Method = req.Method
+		e.Path = req.URL.Path
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return e
+	}
+	var errResp ErrorResponse
+	if err := json.Unmarshal(body, &errResp); err != nil {
+		return e
+	}
+	e.Errors = errResp.Errors
+	return e
+}
+
 // GetErrorMessage reads the response body and returns the error message.
+//
+// Deprecated: Use errors.As with *APIError to inspect API errors.
 func GetErrorMessage(resp *http.Response) string {
 	if resp == nil {
 		return ""
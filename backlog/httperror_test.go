@@ -2,6 +2,8 @@ package backlog
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"testing"
@@ -98,3 +100,129 @@ func TestGetErrorMessage(t *testing.T) {
 		})
 	}
 }
+
+func TestAPIError_Error(t *testing.T) {
+	type expected struct {
+		value string
+	}
+	tests := []struct {
+		name     string
+		err      *APIError
+		expected expected
+	}{
+		{
+			name: "basic",
+			err: &APIError{
+				StatusCode: http.StatusBadRequest,
+				Errors:     []Error{{Message: "Invalid request", Code: 7}},
+			},
+			expected: expected{
+				value: "400: Invalid request",
+			},
+		},
+		{
+			name: "multiple error messages",
+			err: &APIError{
+				StatusCode: http.StatusBadRequest,
+				Errors:     []Error{{Message: "Invalid request", Code: 7}, {Message: "Missing parameter", Code: 7}},
+			},
+			expected: expected{
+				value: "400: Invalid request; Missing parameter",
+			},
+		},
+		{
+			name: "no errors",
+			err: &APIError{
+				StatusCode: http.StatusInternalServerError,
+			},
+			expected: expected{
+				value: "500: Internal Server Error",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected.value, tt.err.Error())
+		})
+	}
+}
+
+func TestAPIError_Is(t *testing.T) {
+	type expected struct {
+		unauthorized bool
+		forbidden    bool
+		notFound     bool
+		rateLimited  bool
+	}
+	tests := []struct {
+		name     string
+		err      error
+		expected expected
+	}{
+		{
+			name: "unauthorized status",
+			err:  &APIError{StatusCode: http.StatusUnauthorized},
+			expected: expected{
+				unauthorized: true,
+			},
+		},
+		{
+			name: "authentication error code",
+			err:  &APIError{StatusCode: http.StatusBadRequest, Errors: []Error{{Code: ErrorCodeAuthentication}}},
+			expected: expected{
+				unauthorized: true,
+			},
+		},
+		{
+			name: "forbidden",
+			err:  &APIError{StatusCode: http.StatusForbidden, Errors: []Error{{Code: ErrorCodeUnauthorizedOperation}}},
+			expected: expected{
+				forbidden: true,
+			},
+		},
+		{
+			name: "not found",
+			err:  &APIError{StatusCode: http.StatusNotFound, Errors: []Error{{Code: ErrorCodeNoResource}}},
+			expected: expected{
+				notFound: true,
+			},
+		},
+		{
+			name: "rate limited",
+			err:  &APIError{StatusCode: http.StatusTooManyRequests, Errors: []Error{{Code: ErrorCodeTooManyRequests}}},
+			expected: expected{
+				rateLimited: true,
+			},
+		},
+		{
+			name: "wrapped",
+			err:  fmt.Errorf("failed to get wiki page: %w", &APIError{StatusCode: http.StatusNotFound}),
+			expected: expected{
+				notFound: true,
+			},
+		},
+		{
+			name:     "internal server error",
+			err:      &APIError{StatusCode: http.StatusInternalServerError},
+			expected: expected{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected.unauthorized, errors.Is(tt.err, ErrUnauthorized))
+			assert.Equal(t, tt.expected.forbidden, errors.Is(tt.err, ErrForbidden))
+			assert.Equal(t, tt.expected.notFound, errors.Is(tt.err, ErrNotFound))
+			assert.Equal(t, tt.expected.rateLimited, errors.Is(tt.err, ErrRateLimited))
+		})
+	}
+}
+
+func TestAPIError_HasCode(t *testing.T) {
+	err := &APIError{
+		StatusCode: http.StatusBadRequest,
+		Errors:     []Error{{Code: ErrorCodeInvalidRequest}, {Code: ErrorCodeNoResource}},
+	}
+	assert.True(t, err.HasCode(ErrorCodeInvalidRequest))
+	assert.True(t, err.HasCode(ErrorCodeNoResource))
+	assert.False(t, err.HasCode(ErrorCodeInternal))
+}
@@ -0,0 +1,389 @@
+package issue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/nekrassov01/backlog-utils/backlog"
+)
+
+const dateLayout = "2006-01-02"
+
+// Client represents a Backlog issue client.
+type Client struct {
+	*backlog.Client
+}
+
+// Issue represents a Backlog issue.
+type Issue struct {
+	ID             int64         `json:"id"`
+	ProjectID      int64         `json:"projectId"`
+	IssueKey       string        `json:"issueKey"`
+	KeyID          int64         `json:"keyId"`
+	IssueType      *IssueType    `json:"issueType,omitempty"`
+	Summary        string        `json:"summary"`
+	Description    string        `json:"description,omitempty"`
+	Priority       *Priority     `json:"priority,omitempty"`
+	Status         *Status       `json:"status,omitempty"`
+	Assignee       *backlog.User `json:"assignee,omitempty"`
+	StartDate      *time.Time    `json:"startDate,omitempty"`
+	DueDate        *time.Time    `json:"dueDate,omitempty"`
+	EstimatedHours *float64      `json:"estimatedHours,omitempty"`
+	ActualHours    *float64      `json:"actualHours,omitempty"`
+	ParentIssueID  *int64        `json:"parentIssueId,omitempty"`
+	CreatedUser    *backlog.User `json:"createdUser,omitempty"`
+	Created        time.Time     `json:"created,omitzero"`
+	UpdatedUser    *backlog.User `json:"updatedUser,omitempty"`
+	Updated        time.Time     `json:"updated,omitzero"`
+}
+
+// IssueType represents the type of an issue.
+type IssueType struct {
+	ID        int64  `json:"id"`
+	ProjectID int64  `json:"projectId"`
+	Name      string `json:"name"`
+	Color     string `json:"color,omitempty"`
+}
+
+// Priority represents the priority of an issue.
+type Priority struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// Status represents the status of an issue.
+type Status struct {
+	ID        int64  `json:"id"`
+	ProjectID int64  `json:"projectId"`
+	Name      string `json:"name"`
+	Color     string `json:"color,omitempty"`
+}
+
+// ListOptions represents the filters for listing and counting issues.
+// Zero values are not sent to the API.
+type ListOptions struct {
+	ProjectIDs     []int64
+	IssueTypeIDs   []int64
+	StatusIDs      []int64
+	PriorityIDs    []int64
+	AssigneeIDs    []int64
+	CreatedUserIDs []int64
+	Keyword        string
+	CreatedSince   time.Time
+	CreatedUntil   time.Time
+	UpdatedSince   time.Time
+	UpdatedUntil   time.Time
+	StartDateSince time.Time
+	StartDateUntil time.Time
+	DueDateSince   time.Time
+	DueDateUntil   time.Time
+	Sort           string
+	Order          string
+	Offset         int
+	Count          int
+}
+
+// CreateInput represents the parameters for creating an issue.
+type CreateInput struct {
+	ProjectID      int64
+	Summary        string
+	IssueTypeID    int64
+	PriorityID     int64
+	Description    string
+	AssigneeID     int64
+	ParentIssueID  int64
+	StartDate      time.Time
+	DueDate        time.Time
+	EstimatedHours *float64
+	ActualHours    *float64
+}
+
+// UpdateInput represents the parameters for updating an issue.
+// Only non-nil fields are sent to the API.
+type UpdateInput struct {
+	Summary        *string
+	Description    *string
+	IssueTypeID    *int64
+	StatusID       *int64
+	PriorityID     *int64
+	AssigneeID     *int64
+	StartDate      *time.Time
+	DueDate        *time.Time
+	EstimatedHours *float64
+	ActualHours    *float64
+	Comment        *string
+}
+
+// NewClient creates a new Backlog issue client.
+func NewClient(url, apiKey string, opts ...backlog.ClientOption) (*Client, error) {
+	o, err := backlog.NewClient(url, apiKey, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{o}, nil
+}
+
+// List returns a list of issues matching the specified options.
+func (c *Client) List(opts *ListOptions) ([]*Issue, error) {
+	return c.ListContext(context.Background(), opts)
+}
+
+// ListContext is like List but uses the specified context for the request.
+func (c *Client) ListContext(ctx context.Context, opts *ListOptions) ([]*Issue, error) {
+	issues, err := backlog.Call[[]*Issue](ctx, c.Client, http.MethodGet, "/api/v2/issues", opts.values(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issues: %w", err)
+	}
+	return issues, nil
+}
+
+// ListAll returns an iterator over all issues matching the specified options.
+// The pages are fetched lazily, using the offset and count of the options as the starting offset and the page size.
+// The iteration stops after maxItems issues if maxItems is positive.
+func (c *Client) ListAll(ctx context.Context, opts *ListOptions, maxItems int) iter.Seq2[*Issue, error] {
+	var base ListOptions
+	if opts != nil {
+		base = *opts
+	}
+	fetch := func(ctx context.Context, p backlog.PageParams) ([]*Issue, error) {
+		o := base
+		o.Offset = p.Offset
+		o.Count = p.Count
+		return c.ListContext(ctx, &o)
+	}
+	return backlog.Paginate(ctx, fetch, &backlog.PaginateOptions{
+		PageSize: base.Count,
+		MaxItems: maxItems,
+		Offset:   base.Offset,
+	})
+}
+
+// Count returns the number of issues matching the specified options.
+// Sort, order, offset and count in the options are ignored.
+func (c *Client) Count(opts *ListOptions) (int, error) {
+	return c.CountContext(context.Background(), opts)
+}
+
+// CountContext is like Count but uses the specified context for the request.
+func (c *Client) CountContext(ctx context.Context, opts *ListOptions) (int, error) {
+	query := opts.values()
+	for _, key := range []string{"sort", "order", "offset", "count"} {
+		query.Del(key)
+	}
+	v, err := backlog.Call[struct {
+		Count int `json:"count"`
+	}](ctx, c.Client, http.MethodGet, "/api/v2/issues/count", query, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count issues: %w", err)
+	}
+	return v.Count, nil
+}
+
+// Get returns an issue by the specified issue ID or key.
+func (c *Client) Get(idOrKey string) (*Issue, error) {
+	return c.GetContext(context.Background(), idOrKey)
+}
+
+// GetContext is like Get but uses the specified context for the request.
+func (c *Client) GetContext(ctx context.Context, idOrKey string) (*Issue, error) {
+	if idOrKey == "" {
+		return nil, errors.New("empty issue key")
+	}
+	issue, err := backlog.Call[*Issue](ctx, c.Client, http.MethodGet, issuePath(idOrKey), nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue: %w", err)
+	}
+	return issue, nil
+}
+
+// Create creates a new issue.
+func (c *Client) Create(in *CreateInput) (*Issue, error) {
+	return c.CreateContext(context.Background(), in)
+}
+
+// CreateContext is like Create but uses the specified context for the request.
+func (c *Client) CreateContext(ctx context.Context, in *CreateInput) (*Issue, error) {
+	if in == nil {
+		return nil, errors.New("empty issue input")
+	}
+	if in.ProjectID <= 0 {
+		return nil, fmt.Errorf("invalid projectId: %d", in.ProjectID)
+	}
+	if in.Summary == "" {
+		return nil, errors.New("empty summary")
+	}
+	if in.IssueTypeID <= 0 {
+		return nil, fmt.Errorf("invalid issueTypeId: %d", in.IssueTypeID)
+	}
+	if in.PriorityID <= 0 {
+		return nil, fmt.Errorf("invalid priorityId: %d", in.PriorityID)
+	}
+	issue, err := backlog.Call[*Issue](ctx, c.Client, http.MethodPost, "/api/v2/issues", nil, in.values())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create issue: %w", err)
+	}
+	return issue, nil
+}
+
+// Update updates an issue by the specified issue ID or key.
+func (c *Client) Update(idOrKey string, in *UpdateInput) (*Issue, error) {
+	return c.UpdateContext(context.Background(), idOrKey, in)
+}
+
+// UpdateContext is like Update but uses the specified context for the request.
+func (c *Client) UpdateContext(ctx context.Context, idOrKey string, in *UpdateInput) (*Issue, error) {
+	if idOrKey == "" {
+		return nil, errors.New("empty issue key")
+	}
+	if in == nil {
+		return nil, errors.New("empty issue input")
+	}
+	values := in.values()
+	if len(values) == 0 {
+		return nil, errors.New("no fields to update")
+	}
+	issue, err := backlog.Call[*Issue](ctx, c.Client, http.MethodPatch, issuePath(idOrKey), nil, values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update issue: %w", err)
+	}
+	return issue, nil
+}
+
+// Delete deletes an issue by the specified issue ID or key and returns the deleted issue.
+func (c *Client) Delete(idOrKey string) (*Issue, error) {
+	return c.DeleteContext(context.Background(), idOrKey)
+}
+
+// DeleteContext is like Delete but uses the specified context for the request.
+func (c *Client) DeleteContext(ctx context.Context, idOrKey string) (*Issue, error) {
+	if idOrKey == "" {
+		return nil, errors.New("empty issue key")
+	}
+	issue, err := backlog.Call[*Issue](ctx, c.Client, http.MethodDelete, issuePath(idOrKey), nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete issue: %w", err)
+	}
+	return issue, nil
+}
+
+func issuePath(idOrKey string) string {
+	return fmt.Sprintf("/api/v2/issues/%s", url.PathEscape(idOrKey))
+}
+
+func (o *ListOptions) values() url.Values {
+	v := url.Values{}
+	if o == nil {
+		return v
+	}
+	addIDs(v, "projectId[]", o.ProjectIDs)
+	addIDs(v, "issueTypeId[]", o.IssueTypeIDs)
+	addIDs(v, "statusId[]", o.StatusIDs)
+	addIDs(v, "priorityId[]", o.PriorityIDs)
+	addIDs(v, "assigneeId[]", o.AssigneeIDs)
+	addIDs(v, "createdUserId[]", o.CreatedUserIDs)
+	addString(v, "keyword", o.Keyword)
+	addDate(v, "createdSince", o.CreatedSince)
+	addDate(v, "createdUntil", o.CreatedUntil)
+	addDate(v, "updatedSince", o.UpdatedSince)
+	addDate(v, "updatedUntil", o.UpdatedUntil)
+	addDate(v, "startDateSince", o.StartDateSince)
+	addDate(v, "startDateUntil", o.StartDateUntil)
+	addDate(v, "dueDateSince", o.DueDateSince)
+	addDate(v, "dueDateUntil", o.DueDateUntil)
+	addString(v, "sort", o.Sort)
+	addString(v, "order", o.Order)
+	if o.Offset > 0 {
+		v.Set("offset", strconv.Itoa(o.Offset))
+	}
+	if o.Count > 0 {
+		v.Set("count", strconv.Itoa(o.Count))
+	}
+	return v
+}
+
+func (in *CreateInput) values() url.Values {
+	v := url.Values{
+		"projectId":   {strconv.FormatInt(in.ProjectID, 10)},
+		"summary":     {in.Summary},
+		"issueTypeId": {strconv.FormatInt(in.IssueTypeID, 10)},
+		"priorityId":  {strconv.FormatInt(in.PriorityID, 10)},
+	}
+	addString(v, "description", in.Description)
+	if in.AssigneeID > 0 {
+		v.Set("assigneeId", strconv.FormatInt(in.AssigneeID, 10))
+	}
+	if in.ParentIssueID > 0 {
+		v.Set("parentIssueId", strconv.FormatInt(in.ParentIssueID, 10))
+	}
+	addDate(v, "startDate", in.StartDate)
+	addDate(v, "dueDate", in.DueDate)
+	if in.EstimatedHours != nil {
+		v.Set("estimatedHours", strconv.FormatFloat(*in.EstimatedHours, 'f', -1, 64))
+	}
+	if in.ActualHours != nil {
+		v.Set("actualHours", strconv.FormatFloat(*in.ActualHours, 'f', -1, 64))
+	}
+	return v
+}
+
+func (in *UpdateInput) values() url.Values {
+	v := url.Values{}
+	if in.Summary != nil {
+		v.Set("summary", *in.Summary)
+	}
+	if in.Description != nil {
+		v.Set("description", *in.Description)
+	}
+	if in.IssueTypeID != nil {
+		v.Set("issueTypeId", strconv.FormatInt(*in.IssueTypeID, 10))
+	}
+	if in.StatusID != nil {
+		v.Set("statusId", strconv.FormatInt(*in.StatusID, 10))
+	}
+	if in.PriorityID != nil {
+		v.Set("priorityId", strconv.FormatInt(*in.PriorityID, 10))
+	}
+	if in.AssigneeID != nil {
+		v.Set("assigneeId", strconv.FormatInt(*in.AssigneeID, 10))
+	}
+	if in.StartDate != nil {
+		v.Set("startDate", in.StartDate.Format(dateLayout))
+	}
+	if in.DueDate != nil {
+		v.Set("dueDate", in.DueDate.Format(dateLayout))
+	}
+	if in.EstimatedHours != nil {
+		v.Set("estimatedHours", strconv.FormatFloat(*in.EstimatedHours, 'f', -1, 64))
+	}
+	if in.ActualHours != nil {
+		v.Set("actualHours", strconv.FormatFloat(*in.ActualHours, 'f', -1, 64))
+	}
+	if in.Comment != nil {
+		v.Set("comment", *in.Comment)
+	}
+	return v
+}
+
+func addIDs(v url.Values, key string, ids []int64) {
+	for _, id := range ids {
+		v.Add(key, strconv.FormatInt(id, 10))
+	}
+}
+
+func addString(v url.Values, key, s string) {
+	if s != "" {
+		v.Set(key, s)
+	}
+}
+
+func addDate(v url.Values, key string, t time.Time) {
+	if !t.IsZero() {
+		v.Set(key, t.Format(dateLayout))
+	}
+}
@@ -0,0 +1,755 @@
+package issue
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/nekrassov01/backlog-utils/backlog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewClient(t *testing.T) {
+	type args struct {
+		url    string
+		apiKey string
+		opts   []backlog.ClientOption
+	}
+	type expected struct {
+		value   *Client
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "basic",
+			args: args{
+				url:    "https://example.com",
+				apiKey: "dummy",
+				opts: []backlog.ClientOption{
+					backlog.WithWriter(io.Discard),
+					backlog.WithTransport(http.DefaultTransport),
+				},
+			},
+			expected: expected{
+				value: &Client{
+					&backlog.Client{
+						Writer:  io.Discard,
+						BaseURL: "https://example.com",
+						APIKey:  "dummy",
+						HTTPClient: &http.Client{
+							Transport: http.DefaultTransport,
+						},
+					},
+				},
+				isError: false,
+			},
+		},
+		{
+			name: "empty api key",
+			args: args{
+				url:    "https://example.com",
+				apiKey: "",
+				opts:   nil,
+			},
+			expected: expected{
+				value:   nil,
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := NewClient(tt.args.url, tt.args.apiKey, tt.args.opts...)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.value, actual)
+		})
+	}
+}
+
+func TestIssue_List(t *testing.T) {
+	type args struct {
+		opts *ListOptions
+	}
+	type expected struct {
+		value   []*Issue
+		isError bool
+	}
+	type mock struct {
+		query  string
+		status int
+		body   string
+	}
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+		mock     mock
+	}{
+		{
+			name: "basic",
+			args: args{
+				opts: &ListOptions{
+					ProjectIDs: []int64{123},
+				},
+			},
+			expected: expected{
+				value: []*Issue{
+					{
+						ID:        1,
+						ProjectID: 123,
+						IssueKey:  "TEST-1",
+						KeyID:     1,
+						Summary:   "first issue",
+						Status:    &Status{ID: 1, ProjectID: 123, Name: "Open"},
+						Created:   time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+					},
+				},
+				isError: false,
+			},
+			mock: mock{
+				query:  "projectId%5B%5D=123",
+				status: 200,
+				body:   `[{"id":1,"projectId":123,"issueKey":"TEST-1","keyId":1,"summary":"first issue","status":{"id":1,"projectId":123,"name":"Open"},"startDate":null,"created":"2025-04-01T00:00:00Z"}]`,
+			},
+		},
+		{
+			name: "filters",
+			args: args{
+				opts: &ListOptions{
+					ProjectIDs:   []int64{123, 456},
+					StatusIDs:    []int64{1},
+					AssigneeIDs:  []int64{9},
+					Keyword:      "bug",
+					CreatedSince: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+					DueDateUntil: time.Date(2025, 4, 30, 0, 0, 0, 0, time.UTC),
+					Sort:         "updated",
+					Order:        "asc",
+					Offset:       20,
+					Count:        100,
+				},
+			},
+			expected: expected{
+				value:   []*Issue{},
+				isError: false,
+			},
+			mock: mock{
+				query:  "assigneeId%5B%5D=9&count=100&createdSince=2025-04-01&dueDateUntil=2025-04-30&keyword=bug&offset=20&order=asc&projectId%5B%5D=123&projectId%5B%5D=456&sort=updated&statusId%5B%5D=1",
+				status: 200,
+				body:   `[]`,
+			},
+		},
+		{
+			name: "api error",
+			args: args{
+				opts: nil,
+			},
+			expected: expected{
+				value:   nil,
+				isError: true,
+			},
+			mock: mock{
+				query:  "",
+				status: 500,
+				body:   `{"errors":[{"message":"Internal Server Error"}]}`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := newTestClient()
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				"https://example.com/api/v2/issues?"+withAPIKey(tt.mock.query),
+				httpmock.NewStringResponder(tt.mock.status, tt.mock.body),
+			)
+			actual, err := o.List(tt.args.opts)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.value, actual)
+		})
+	}
+}
+
+func TestIssue_ListAll(t *testing.T) {
+	type args struct {
+		opts     *ListOptions
+		maxItems int
+	}
+	type expected struct {
+		value   []int64
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "basic",
+			args: args{
+				opts:     &ListOptions{ProjectIDs: []int64{123}, Count: 2},
+				maxItems: 0,
+			},
+			expected: expected{
+				value:   []int64{1, 2, 3},
+				isError: false,
+			},
+		},
+		{
+			name: "max items",
+			args: args{
+				opts:     &ListOptions{ProjectIDs: []int64{123}, Count: 2},
+				maxItems: 1,
+			},
+			expected: expected{
+				value:   []int64{1},
+				isError: false,
+			},
+		},
+		{
+			name: "api error",
+			args: args{
+				opts:     &ListOptions{ProjectIDs: []int64{999}, Count: 2},
+				maxItems: 0,
+			},
+			expected: expected{
+				value:   []int64{},
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := newTestClient()
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				"https://example.com/api/v2/issues?count=2&projectId%5B%5D=123&apiKey=dummy",
+				httpmock.NewStringResponder(200, `[{"id":1},{"id":2}]`),
+			)
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				"https://example.com/api/v2/issues?count=1&projectId%5B%5D=123&apiKey=dummy",
+				httpmock.NewStringResponder(200, `[{"id":1}]`),
+			)
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				"https://example.com/api/v2/issues?count=2&offset=2&projectId%5B%5D=123&apiKey=dummy",
+				httpmock.NewStringResponder(200, `[{"id":3}]`),
+			)
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				"https://example.com/api/v2/issues?count=2&projectId%5B%5D=999&apiKey=dummy",
+				httpmock.NewStringResponder(404, `{"errors":[{"message":"No project.","code":6}]}`),
+			)
+			actual := []int64{}
+			var err error
+			for i, e := range o.ListAll(context.Background(), tt.args.opts, tt.args.maxItems) {
+				if e != nil {
+					err = e
+					break
+				}
+				actual = append(actual, i.ID)
+			}
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.value, actual)
+		})
+	}
+}
+
+func TestIssue_Count(t *testing.T) {
+	type args struct {
+		opts *ListOptions
+	}
+	type expected struct {
+		value   int
+		isError bool
+	}
+	type mock struct {
+		query  string
+		status int
+		body   string
+	}
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+		mock     mock
+	}{
+		{
+			name: "basic",
+			args: args{
+				opts: &ListOptions{
+					ProjectIDs: []int64{123},
+					Sort:       "updated",
+					Offset:     20,
+					Count:      100,
+				},
+			},
+			expected: expected{
+				value:   42,
+				isError: false,
+			},
+			mock: mock{
+				query:  "projectId%5B%5D=123",
+				status: 200,
+				body:   `{"count":42}`,
+			},
+		},
+		{
+			name: "api error",
+			args: args{
+				opts: &ListOptions{},
+			},
+			expected: expected{
+				value:   0,
+				isError: true,
+			},
+			mock: mock{
+				query:  "",
+				status: 401,
+				body:   `{"errors":[{"message":"Authentication failure.","code":11}]}`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := newTestClient()
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				"https://example.com/api/v2/issues/count?"+withAPIKey(tt.mock.query),
+				httpmock.NewStringResponder(tt.mock.status, tt.mock.body),
+			)
+			actual, err := o.Count(tt.args.opts)
+			if tt.expected.isError {
+				assert.ErrorIs(t, err, backlog.ErrUnauthorized)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.value, actual)
+		})
+	}
+}
+
+func TestIssue_Get(t *testing.T) {
+	type args struct {
+		idOrKey string
+	}
+	type expected struct {
+		value   *Issue
+		isError bool
+	}
+	type mock struct {
+		status int
+		body   string
+	}
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+		mock     mock
+	}{
+		{
+			name: "basic",
+			args: args{
+				idOrKey: "TEST-1",
+			},
+			expected: expected{
+				value: &Issue{
+					ID:        1,
+					ProjectID: 123,
+					IssueKey:  "TEST-1",
+					Summary:   "first issue",
+					Assignee:  &backlog.User{ID: 9, UserID: "admin", Name: "Admin"},
+				},
+				isError: false,
+			},
+			mock: mock{
+				status: 200,
+				body:   `{"id":1,"projectId":123,"issueKey":"TEST-1","summary":"first issue","assignee":{"id":9,"userId":"admin","name":"Admin"}}`,
+			},
+		},
+		{
+			name: "empty key",
+			args: args{
+				idOrKey: "",
+			},
+			expected: expected{
+				value:   nil,
+				isError: true,
+			},
+			mock: mock{
+				status: 0,
+			},
+		},
+		{
+			name: "api error",
+			args: args{
+				idOrKey: "TEST-1",
+			},
+			expected: expected{
+				value:   nil,
+				isError: true,
+			},
+			mock: mock{
+				status: 404,
+				body:   `{"errors":[{"message":"No issue.","code":6}]}`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := newTestClient()
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			if tt.mock.status != 0 {
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					"https://example.com/api/v2/issues/"+tt.args.idOrKey+"?apiKey=dummy",
+					httpmock.NewStringResponder(tt.mock.status, tt.mock.body),
+				)
+			}
+			actual, err := o.GetContext(context.Background(), tt.args.idOrKey)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.value, actual)
+		})
+	}
+}
+
+func TestIssue_Create(t *testing.T) {
+	hours := 1.5
+	type args struct {
+		in *CreateInput
+	}
+	type expected struct {
+		value   *Issue
+		body    string
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "basic",
+			args: args{
+				in: &CreateInput{
+					ProjectID:      123,
+					Summary:        "new issue",
+					IssueTypeID:    2,
+					PriorityID:     3,
+					Description:    "details",
+					AssigneeID:     9,
+					DueDate:        time.Date(2025, 4, 30, 0, 0, 0, 0, time.UTC),
+					EstimatedHours: &hours,
+				},
+			},
+			expected: expected{
+				value: &Issue{
+					ID:        2,
+					ProjectID: 123,
+					IssueKey:  "TEST-2",
+					Summary:   "new issue",
+				},
+				body:    "assigneeId=9&description=details&dueDate=2025-04-30&estimatedHours=1.5&issueTypeId=2&priorityId=3&projectId=123&summary=new+issue",
+				isError: false,
+			},
+		},
+		{
+			name: "nil input",
+			args: args{
+				in: nil,
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name: "invalid project id",
+			args: args{
+				in: &CreateInput{Summary: "new issue", IssueTypeID: 2, PriorityID: 3},
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name: "empty summary",
+			args: args{
+				in: &CreateInput{ProjectID: 123, IssueTypeID: 2, PriorityID: 3},
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name: "invalid issue type id",
+			args: args{
+				in: &CreateInput{ProjectID: 123, Summary: "new issue", PriorityID: 3},
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name: "invalid priority id",
+			args: args{
+				in: &CreateInput{ProjectID: 123, Summary: "new issue", IssueTypeID: 2},
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := newTestClient()
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			var body string
+			httpmock.RegisterResponder(
+				http.MethodPost,
+				"https://example.com/api/v2/issues?apiKey=dummy",
+				func(req *http.Request) (*http.Response, error) {
+					b, err := io.ReadAll(req.Body)
+					if err != nil {
+						return nil, err
+					}
+					body = string(b)
+					return httpmock.NewStringResponse(201, `{"id":2,"projectId":123,"issueKey":"TEST-2","summary":"new issue"}`), nil
+				},
+			)
+			actual, err := o.Create(tt.args.in)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.value, actual)
+			assert.Equal(t, tt.expected.body, body)
+		})
+	}
+}
+
+func TestIssue_Update(t *testing.T) {
+	summary := "renamed"
+	statusID := int64(4)
+	comment := "closing"
+	type args struct {
+		idOrKey string
+		in      *UpdateInput
+	}
+	type expected struct {
+		body    string
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "basic",
+			args: args{
+				idOrKey: "TEST-1",
+				in: &UpdateInput{
+					Summary:  &summary,
+					StatusID: &statusID,
+					Comment:  &comment,
+				},
+			},
+			expected: expected{
+				body:    "comment=closing&statusId=4&summary=renamed",
+				isError: false,
+			},
+		},
+		{
+			name: "empty key",
+			args: args{
+				idOrKey: "",
+				in:      &UpdateInput{Summary: &summary},
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name: "nil input",
+			args: args{
+				idOrKey: "TEST-1",
+				in:      nil,
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name: "no fields",
+			args: args{
+				idOrKey: "TEST-1",
+				in:      &UpdateInput{},
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := newTestClient()
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			var body string
+			httpmock.RegisterResponder(
+				http.MethodPatch,
+				"https://example.com/api/v2/issues/TEST-1?apiKey=dummy",
+				func(req *http.Request) (*http.Response, error) {
+					b, err := io.ReadAll(req.Body)
+					if err != nil {
+						return nil, err
+					}
+					body = string(b)
+					return httpmock.NewStringResponse(200, `{"id":1,"issueKey":"TEST-1","summary":"renamed"}`), nil
+				},
+			)
+			actual, err := o.Update(tt.args.idOrKey, tt.args.in)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "renamed", actual.Summary)
+			assert.Equal(t, tt.expected.body, body)
+		})
+	}
+}
+
+func TestIssue_Delete(t *testing.T) {
+	type args struct {
+		idOrKey string
+	}
+	type expected struct {
+		value   *Issue
+		isError bool
+	}
+	type mock struct {
+		status int
+		body   string
+	}
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+		mock     mock
+	}{
+		{
+			name: "basic",
+			args: args{
+				idOrKey: "TEST-1",
+			},
+			expected: expected{
+				value: &Issue{
+					ID:       1,
+					IssueKey: "TEST-1",
+				},
+				isError: false,
+			},
+			mock: mock{
+				status: 200,
+				body:   `{"id":1,"issueKey":"TEST-1"}`,
+			},
+		},
+		{
+			name: "empty key",
+			args: args{
+				idOrKey: "",
+			},
+			expected: expected{
+				isError: true,
+			},
+			mock: mock{
+				status: 0,
+			},
+		},
+		{
+			name: "api error",
+			args: args{
+				idOrKey: "TEST-1",
+			},
+			expected: expected{
+				isError: true,
+			},
+			mock: mock{
+				status: 403,
+				body:   `{"errors":[{"message":"Forbidden","code":5}]}`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := newTestClient()
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			if tt.mock.status != 0 {
+				httpmock.RegisterResponder(
+					http.MethodDelete,
+					"https://example.com/api/v2/issues/"+tt.args.idOrKey+"?apiKey=dummy",
+					httpmock.NewStringResponder(tt.mock.status, tt.mock.body),
+				)
+			}
+			actual, err := o.Delete(tt.args.idOrKey)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.value, actual)
+		})
+	}
+}
+
+func newTestClient() *Client {
+	return &Client{
+		Client: &backlog.Client{
+			Writer:     io.Discard,
+			BaseURL:    "https://example.com",
+			APIKey:     "dummy",
+			HTTPClient: &http.Client{},
+		},
+	}
+}
+
+func withAPIKey(query string) string {
+	if query == "" {
+		return "apiKey=dummy"
+	}
+	return query + "&apiKey=dummy"
+}
@@ -0,0 +1,157 @@
+package backlog
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultRateLimitThreshold is the fraction of the rate limit below which RateLimiter starts pacing requests.
+const DefaultRateLimitThreshold = 0.5
+
+var _ http.RoundTripper = (*RateLimiter)(nil)
+
+// RateLimitCategory represents a group of Backlog APIs that share a rate limit.
+type RateLimitCategory string
+
+const (
+	RateLimitRead   RateLimitCategory = "read"
+	RateLimitUpdate RateLimitCategory = "update"
+	RateLimitSearch RateLimitCategory = "search"
+	RateLimitIcon   RateLimitCategory = "icon"
+)
+
+// RateLimitCategoryOf returns the rate limit category of the request.
+// Requests other than GET are updates, and GET requests are reads unless they search issues or fetch images.
+func RateLimitCategoryOf(req *http.Request) RateLimitCategory {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return RateLimitUpdate
+	}
+	p := strings.TrimSuffix(req.URL.Path, "/")
+	switch {
+	case p == "/api/v2/issues" || p == "/api/v2/issues/count":
+		return RateLimitSearch
+	case strings.HasSuffix(p, "/icon") || strings.HasSuffix(p, "/image"):
+		return RateLimitIcon
+	}
+	return RateLimitRead
+}
+
+// RateLimiter is a custom HTTP transport that paces requests so that they stay within the rate limit.
+// It tracks the X-RateLimit-* headers of the responses for each category, and once the remaining
+// requests fall below Threshold of the limit, it spreads them evenly until the limit is reset.
+// When nothing remains, requests wait until the reset. It is safe for concurrent use.
+type RateLimiter struct {
+	Transport http.RoundTripper `json:"-"`
+	Threshold float64           `json:"threshold"`
+
+	mu      sync.Mutex
+	budgets map[RateLimitCategory]*rateBudget
+}
+
+type rateBudget struct {
+	RateLimit
+	next time.Time
+}
+
+// NewRateLimiter creates a new RateLimiter with DefaultRateLimitThreshold.
+func NewRateLimiter(transport http.RoundTripper) *RateLimiter {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &RateLimiter{
+		Transport: transport,
+		Threshold: DefaultRateLimitThreshold,
+	}
+}
+
+// RoundTrip waits for the turn of the request in its category, sends it and records the rate limit of the response.
+func (l *RateLimiter) RoundTrip(req *http.Request) (*http.Response, error) {
+	c := RateLimitCategoryOf(req)
+	if err := sleep(req.Context(), l.reserve(c, nowFunc())); err != nil {
+		return nil, err
+	}
+	resp, err := l.Transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if rl := ParseRateLimit(resp.Header); rl != nil {
+		l.update(c, rl)
+	}
+	return resp, nil
+}
+
+// Budget returns the last known rate limit of the category, counting the requests sent since then.
+// It returns nil if no response of the category has been seen.
+func (l *RateLimiter) Budget(c RateLimitCategory) *RateLimit {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, ok := l.budgets[c]
+	if !ok {
+		return nil
+	}
+	rl := b.RateLimit
+	return &rl
+}
+
+// reserve takes a request from the budget of the category and returns how long it must wait.
+func (l *RateLimiter) reserve(c RateLimitCategory, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, ok := l.budgets[c]
+	if !ok || !now.Before(b.Reset) {
+		return 0
+	}
+	if b.Remaining <= 0 {
+		return b.Reset.Sub(now)
+	}
+
+	at := now
+	if float64(b.Remaining) < l.Threshold*float64(b.Limit) {
+		if b.next.After(at) {
+			at = b.next
+		}
+		b.next = at.Add(b.Reset.Sub(now) / time.Duration(b.Remaining))
+	}
+	b.Remaining--
+	return at.Sub(now)
+}
+
+// update records the rate limit of a response. Responses of the same window that arrive
+// out of order never increase the remaining requests.
+func (l *RateLimiter) update(c RateLimitCategory, rl *RateLimit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.budgets == nil {
+		l.budgets = map[RateLimitCategory]*rateBudget{}
+	}
+	b, ok := l.budgets[c]
+	if !ok {
+		l.budgets[c] = &rateBudget{RateLimit: *rl}
+		return
+	}
+	if b.Reset.Equal(rl.Reset) {
+		if b.Remaining < rl.Remaining {
+			return
+		}
+	} else {
+		b.next = time.Time{}
+	}
+	b.RateLimit = *rl
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
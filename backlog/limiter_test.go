@@ -0,0 +1,178 @@
+package backlog
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitCategoryOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		url      string
+		expected RateLimitCategory
+	}{
+		{
+			name:     "read",
+			method:   http.MethodGet,
+			url:      "https://example.com/api/v2/wikis/1",
+			expected: RateLimitRead,
+		},
+		{
+			name:     "update",
+			method:   http.MethodPatch,
+			url:      "https://example.com/api/v2/wikis/1",
+			expected: RateLimitUpdate,
+		},
+		{
+			name:     "delete",
+			method:   http.MethodDelete,
+			url:      "https://example.com/api/v2/issues/TEST-1",
+			expected: RateLimitUpdate,
+		},
+		{
+			name:     "search",
+			method:   http.MethodGet,
+			url:      "https://example.com/api/v2/issues?count=100",
+			expected: RateLimitSearch,
+		},
+		{
+			name:     "count",
+			method:   http.MethodGet,
+			url:      "https://example.com/api/v2/issues/count",
+			expected: RateLimitSearch,
+		},
+		{
+			name:     "get issue",
+			method:   http.MethodGet,
+			url:      "https://example.com/api/v2/issues/TEST-1",
+			expected: RateLimitRead,
+		},
+		{
+			name:     "icon",
+			method:   http.MethodGet,
+			url:      "https://example.com/api/v2/users/1/icon",
+			expected: RateLimitIcon,
+		},
+		{
+			name:     "space image",
+			method:   http.MethodGet,
+			url:      "https://example.com/api/v2/space/image",
+			expected: RateLimitIcon,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequestWithContext(context.Background(), tt.method, tt.url, nil)
+			assert.Equal(t, tt.expected, RateLimitCategoryOf(req))
+		})
+	}
+}
+
+func TestRateLimiter_reserve(t *testing.T) {
+	now := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	type args struct {
+		budget *RateLimit
+		n      int
+	}
+	tests := []struct {
+		name     string
+		args     args
+		expected []time.Duration
+	}{
+		{
+			name:     "unknown",
+			args:     args{n: 2},
+			expected: []time.Duration{0, 0},
+		},
+		{
+			name:     "above threshold",
+			args:     args{budget: &RateLimit{Limit: 100, Remaining: 80, Reset: now.Add(time.Minute)}, n: 2},
+			expected: []time.Duration{0, 0},
+		},
+		{
+			name:     "below threshold",
+			args:     args{budget: &RateLimit{Limit: 100, Remaining: 3, Reset: now.Add(time.Minute)}, n: 3},
+			expected: []time.Duration{0, 20 * time.Second, 50 * time.Second},
+		},
+		{
+			name:     "exhausted",
+			args:     args{budget: &RateLimit{Limit: 100, Remaining: 0, Reset: now.Add(time.Minute)}, n: 2},
+			expected: []time.Duration{time.Minute, time.Minute},
+		},
+		{
+			name:     "reset passed",
+			args:     args{budget: &RateLimit{Limit: 100, Remaining: 0, Reset: now}, n: 1},
+			expected: []time.Duration{0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewRateLimiter(nil)
+			if tt.args.budget != nil {
+				l.update(RateLimitRead, tt.args.budget)
+			}
+			actual := make([]time.Duration, tt.args.n)
+			for i := range actual {
+				actual[i] = l.reserve(RateLimitRead, now)
+			}
+			assert.Equal(t, tt.expected, actual)
+			assert.Nil(t, l.Budget(RateLimitUpdate))
+		})
+	}
+}
+
+func TestRateLimiter_update(t *testing.T) {
+	reset := time.Unix(1743465600, 0)
+	l := NewRateLimiter(nil)
+	assert.Nil(t, l.Budget(RateLimitRead))
+
+	l.update(RateLimitRead, &RateLimit{Limit: 600, Remaining: 500, Reset: reset})
+	l.update(RateLimitRead, &RateLimit{Limit: 600, Remaining: 510, Reset: reset})
+	assert.Equal(t, &RateLimit{Limit: 600, Remaining: 500, Reset: reset}, l.Budget(RateLimitRead))
+
+	l.update(RateLimitRead, &RateLimit{Limit: 600, Remaining: 599, Reset: reset.Add(time.Minute)})
+	assert.Equal(t, &RateLimit{Limit: 600, Remaining: 599, Reset: reset.Add(time.Minute)}, l.Budget(RateLimitRead))
+	assert.Nil(t, l.Budget(RateLimitUpdate))
+}
+
+func TestRateLimiter_RoundTrip(t *testing.T) {
+	reset := time.Now().Add(1 * time.Hour).Unix()
+	l := NewRateLimiter(&mockRoundTripper{
+		responses: []*http.Response{
+			{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewBufferString("ok")),
+				Header: http.Header{
+					limitHeaderKey:     {"150"},
+					remainingHeaderKey: {"0"},
+					resetHeaderKey:     {strconv.FormatInt(reset, 10)},
+				},
+			},
+		},
+	})
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodPatch, "https://example.com/api/v2/wikis/1", nil)
+	resp, err := l.RoundTrip(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, &RateLimit{Limit: 150, Remaining: 0, Reset: time.Unix(reset, 0)}, l.Budget(RateLimitUpdate))
+
+	// Reads have their own budget and are not held back by the exhausted updates.
+	req, _ = http.NewRequestWithContext(context.Background(), http.MethodGet, "https://example.com/api/v2/wikis/1", nil)
+	_, err = l.RoundTrip(req)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req, _ = http.NewRequestWithContext(ctx, http.MethodPatch, "https://example.com/api/v2/wikis/1", nil)
+	_, err = l.RoundTrip(req)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,120 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogin(t *testing.T) {
+	srv, _ := newTokenServer(t)
+	type args struct {
+		redirectURL string
+		callback    func(q url.Values) url.Values
+	}
+	type expected struct {
+		token   string
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "basic",
+			args: args{
+				redirectURL: "http://127.0.0.1:0/callback",
+				callback: func(q url.Values) url.Values {
+					return url.Values{"code": {"code"}, "state": {q.Get("state")}}
+				},
+			},
+			expected: expected{
+				token:   "access1",
+				isError: false,
+			},
+		},
+		{
+			name: "state mismatch",
+			args: args{
+				redirectURL: "http://127.0.0.1:0/callback",
+				callback: func(url.Values) url.Values {
+					return url.Values{"code": {"code"}, "state": {"forged"}}
+				},
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name: "access denied",
+			args: args{
+				redirectURL: "http://127.0.0.1:0/callback",
+				callback: func(q url.Values) url.Values {
+					return url.Values{"error": {"access_denied"}, "state": {q.Get("state")}}
+				},
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name: "not loopback",
+			args: args{
+				redirectURL: "http://example.com/callback",
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Config{BaseURL: srv.URL, ClientID: "id", ClientSecret: "secret", RedirectURL: tt.args.redirectURL}
+			open := func(authURL string) error {
+				u, err := url.Parse(authURL)
+				if err != nil {
+					return err
+				}
+				q := tt.args.callback(u.Query())
+				go func() {
+					req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, u.Query().Get("redirect_uri")+"?"+q.Encode(), nil)
+					if resp, err := http.DefaultClient.Do(req); err == nil {
+						_ = resp.Body.Close()
+					}
+				}()
+				return nil
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			actual, err := Login(ctx, c, open)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.token, actual.AccessToken)
+			assert.NotContains(t, c.RedirectURL, ":0/")
+		})
+	}
+}
+
+func TestLogin_Canceled(t *testing.T) {
+	c := &Config{RedirectURL: "http://127.0.0.1:0/callback"}
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err := Login(ctx, c, func(string) error {
+		cancel()
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, err = Login(context.Background(), c, func(string) error {
+		return errors.New("error")
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,133 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	authorizePath = "/OAuth2AccessRequest.action"
+	tokenPath     = "/api/v2/oauth2/token"
+
+	// expiryDelta is subtracted from the expiry so that a token is refreshed before it expires in flight.
+	expiryDelta = 30 * time.Second
+)
+
+var nowFunc = time.Now
+
+// Config represents an OAuth 2.0 application registered in a Backlog space.
+type Config struct {
+	BaseURL      string       `json:"baseUrl"`
+	ClientID     string       `json:"clientId"`
+	ClientSecret string       `json:"-"`
+	RedirectURL  string       `json:"redirectUrl"`
+	HTTPClient   *http.Client `json:"-"`
+}
+
+// Token represents an OAuth 2.0 token issued by Backlog.
+type Token struct {
+	AccessToken  string    `json:"access_token"`
+	TokenType    string    `json:"token_type"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresIn    int64     `json:"expires_in,omitempty"`
+	Expiry       time.Time `json:"expiry,omitzero"`
+}
+
+// Valid reports whether the token has an access token that is not about to expire.
+func (t *Token) Valid() bool {
+	if t == nil || t.AccessToken == "" {
+		return false
+	}
+	return t.Expiry.IsZero() || nowFunc().Add(expiryDelta).Before(t.Expiry)
+}
+
+// AuthCodeURL returns the URL of the consent page that redirects to RedirectURL with an authorization code.
+func (c *Config) AuthCodeURL(state string) string {
+	q := url.Values{
+		"response_type": {"code"},
+		"client_id":     {c.ClientID},
+		"redirect_uri":  {c.RedirectURL},
+		"state":         {state},
+	}
+	return strings.TrimSuffix(c.BaseURL, "/") + authorizePath + "?" + q.Encode()
+}
+
+// Exchange exchanges an authorization code for a token.
+func (c *Config) Exchange(ctx context.Context, code string) (*Token, error) {
+	if code == "" {
+		return nil, errors.New("empty authorization code")
+	}
+	return c.retrieve(ctx, url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {c.RedirectURL},
+	})
+}
+
+// Refresh returns a new token issued with the refresh token.
+func (c *Config) Refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	if refreshToken == "" {
+		return nil, errors.New("empty refresh token")
+	}
+	return c.retrieve(ctx, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	})
+}
+
+func (c *Config) retrieve(ctx context.Context, form url.Values) (*Token, error) {
+	if c.BaseURL == "" {
+		return nil, errors.New("empty URL")
+	}
+	if c.ClientID == "" || c.ClientSecret == "" {
+		return nil, errors.New("empty client credentials")
+	}
+	form.Set("client_id", c.ClientID)
+	form.Set("client_secret", c.ClientSecret)
+
+	u := strings.TrimSuffix(c.BaseURL, "/") + tokenPath
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve token: %w", err)
+	}
+
+	//nolint:errcheck
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve token: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to retrieve token: %d: %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	t := &Token{}
+	if err := json.Unmarshal(body, t); err != nil {
+		return nil, fmt.Errorf("failed to decode token: %w", err)
+	}
+	if t.AccessToken == "" {
+		return nil, errors.New("empty access token in token response")
+	}
+	if t.ExpiresIn > 0 {
+		t.Expiry = nowFunc().Add(time.Duration(t.ExpiresIn) * time.Second)
+	}
+	return t, nil
+}
@@ -0,0 +1,182 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTokenServer returns a stand-in Backlog token endpoint that issues numbered access tokens.
+func newTokenServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var n atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != tokenPath || r.Method != http.MethodPost {
+			http.NotFound(w, r)
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if r.PostForm.Get("client_id") != "id" || r.PostForm.Get("client_secret") != "secret" {
+			http.Error(w, "invalid client", http.StatusUnauthorized)
+			return
+		}
+		switch r.PostForm.Get("grant_type") {
+		case "authorization_code":
+			if r.PostForm.Get("code") != "code" {
+				http.Error(w, "invalid code", http.StatusBadRequest)
+				return
+			}
+		case "refresh_token":
+			if r.PostForm.Get("refresh_token") != "refresh" {
+				http.Error(w, "invalid refresh token", http.StatusBadRequest)
+				return
+			}
+		default:
+			http.Error(w, "unsupported grant type", http.StatusBadRequest)
+			return
+		}
+		i := n.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"access_token":"access%d","token_type":"Bearer","expires_in":3600,"refresh_token":"refresh"}`, i)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &n
+}
+
+func TestConfig_AuthCodeURL(t *testing.T) {
+	c := &Config{
+		BaseURL:     "https://example.backlog.com/",
+		ClientID:    "id",
+		RedirectURL: "http://localhost:8080/callback",
+	}
+	assert.Equal(t,
+		"https://example.backlog.com/OAuth2AccessRequest.action?client_id=id&redirect_uri=http%3A%2F%2Flocalhost%3A8080%2Fcallback&response_type=code&state=xyz",
+		c.AuthCodeURL("xyz"),
+	)
+}
+
+func TestConfig_Exchange(t *testing.T) {
+	srv, _ := newTokenServer(t)
+	now := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	nowFunc = func() time.Time { return now }
+	defer func() { nowFunc = time.Now }()
+
+	type args struct {
+		config *Config
+		code   string
+	}
+	type expected struct {
+		value   *Token
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "basic",
+			args: args{
+				config: &Config{BaseURL: srv.URL, ClientID: "id", ClientSecret: "secret"},
+				code:   "code",
+			},
+			expected: expected{
+				value: &Token{
+					AccessToken:  "access1",
+					TokenType:    "Bearer",
+					RefreshToken: "refresh",
+					ExpiresIn:    3600,
+					Expiry:       now.Add(1 * time.Hour),
+				},
+				isError: false,
+			},
+		},
+		{
+			name: "invalid code",
+			args: args{
+				config: &Config{BaseURL: srv.URL, ClientID: "id", ClientSecret: "secret"},
+				code:   "invalid",
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name: "empty code",
+			args: args{
+				config: &Config{BaseURL: srv.URL, ClientID: "id", ClientSecret: "secret"},
+				code:   "",
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name: "invalid client",
+			args: args{
+				config: &Config{BaseURL: srv.URL, ClientID: "id", ClientSecret: "wrong"},
+				code:   "code",
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name: "empty client credentials",
+			args: args{
+				config: &Config{BaseURL: srv.URL},
+				code:   "code",
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.args.config.Exchange(context.Background(), tt.args.code)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.value, actual)
+		})
+	}
+}
+
+func TestConfig_Refresh(t *testing.T) {
+	srv, _ := newTokenServer(t)
+	c := &Config{BaseURL: srv.URL, ClientID: "id", ClientSecret: "secret"}
+
+	actual, err := c.Refresh(context.Background(), "refresh")
+	assert.NoError(t, err)
+	assert.Equal(t, "access1", actual.AccessToken)
+
+	_, err = c.Refresh(context.Background(), "invalid")
+	assert.Error(t, err)
+
+	_, err = c.Refresh(context.Background(), "")
+	assert.Error(t, err)
+}
+
+func TestToken_Valid(t *testing.T) {
+	now := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	nowFunc = func() time.Time { return now }
+	defer func() { nowFunc = time.Now }()
+
+	assert.False(t, (*Token)(nil).Valid())
+	assert.False(t, (&Token{}).Valid())
+	assert.True(t, (&Token{AccessToken: "a"}).Valid())
+	assert.True(t, (&Token{AccessToken: "a", Expiry: now.Add(1 * time.Minute)}).Valid())
+	assert.False(t, (&Token{AccessToken: "a", Expiry: now.Add(10 * time.Second)}).Valid())
+}
@@ -0,0 +1,74 @@
+package oauth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// ErrNoToken is returned by a Store that has no token saved.
+var ErrNoToken = errors.New("no token: run login first")
+
+// Store loads and saves tokens.
+type Store interface {
+	Load() (*Token, error)
+	Save(t *Token) error
+}
+
+// FileStore stores a token as a JSON file that only the owner can read.
+type FileStore struct {
+	Path string
+}
+
+// Load reads the token from the file. It returns ErrNoToken if the file does not exist.
+func (s *FileStore) Load() (*Token, error) {
+	b, err := os.ReadFile(filepath.Clean(s.Path))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrNoToken
+		}
+		return nil, fmt.Errorf("failed to load token: %w", err)
+	}
+	t := &Token{}
+	if err := json.Unmarshal(b, t); err != nil {
+		return nil, fmt.Errorf("failed to decode token: %w", err)
+	}
+	return t, nil
+}
+
+// Save writes the token to the file, creating the parent directory if needed.
+// The file is replaced atomically so that a concurrent Load never sees a partial token.
+func (s *FileStore) Save(t *Token) error {
+	if t == nil {
+		return errors.New("empty token")
+	}
+	b, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(s.Path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to save token: %w", err)
+	}
+	f, err := os.CreateTemp(dir, ".token-*")
+	if err != nil {
+		return fmt.Errorf("failed to save token: %w", err)
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+
+	if _, err := f.Write(b); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to save token: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to save token: %w", err)
+	}
+	if err := os.Rename(f.Name(), s.Path); err != nil {
+		return fmt.Errorf("failed to save token: %w", err)
+	}
+	return nil
+}
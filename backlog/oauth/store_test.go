@@ -0,0 +1,31 @@
+package oauth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileStore(t *testing.T) {
+	s := &FileStore{Path: filepath.Join(t.TempDir(), "bkl", "token.json")}
+
+	_, err := s.Load()
+	assert.ErrorIs(t, err, ErrNoToken)
+
+	tok := &Token{AccessToken: "access", TokenType: "Bearer", RefreshToken: "refresh"}
+	assert.NoError(t, s.Save(tok))
+	info, err := os.Stat(s.Path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	actual, err := s.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, tok, actual)
+
+	assert.Error(t, s.Save(nil))
+	assert.NoError(t, os.WriteFile(s.Path, []byte("{"), 0o600))
+	_, err = s.Load()
+	assert.Error(t, err)
+}
@@ -0,0 +1,143 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/nekrassov01/backlog-utils/backlog"
+)
+
+var _ backlog.Authenticator = (*Transport)(nil)
+
+// Transport is an http.RoundTripper that authenticates requests with an OAuth 2.0 access token.
+// The token is loaded from Store, refreshed when it expires or is rejected, and saved back.
+// It stacks on top of Base, which is typically a *backlog.RetryableTransport.
+//
+// Transport also implements backlog.Authenticator so that it can be passed to backlog.WithAuthenticator:
+// the header is set by RoundTrip, and the tokens are redacted from the errors of the client.
+type Transport struct {
+	Base   http.RoundTripper
+	Config *Config
+	Store  Store
+
+	mu    sync.Mutex
+	token *Token
+}
+
+// NewTransport creates a new Transport. If base is nil, http.DefaultTransport is used.
+func NewTransport(config *Config, store Store, base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{
+		Base:   base,
+		Config: config,
+		Store:  store,
+	}
+}
+
+// RoundTrip sends the request with the access token in the Authorization header.
+// A 401 response is retried once with a refreshed token if the request body can be rewound.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	tok, err := t.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.Base.RoundTrip(authorize(req, tok))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || tok.RefreshToken == "" {
+		return resp, err
+	}
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+
+	tok, err = t.refresh(ctx, tok)
+	if err != nil {
+		return resp, nil
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	r := authorize(req, tok)
+	if req.GetBody != nil {
+		if r.Body, err = req.GetBody(); err != nil {
+			return nil, fmt.Errorf("failed to rewind request body: %w", err)
+		}
+	}
+	return t.Base.RoundTrip(r)
+}
+
+// Token returns a valid token, loading it from Store or refreshing it as needed.
+func (t *Transport) Token(ctx context.Context) (*Token, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token == nil {
+		tok, err := t.Store.Load()
+		if err != nil {
+			return nil, err
+		}
+		t.token = tok
+	}
+	if t.token.Valid() {
+		return t.token, nil
+	}
+	return t.refreshLocked(ctx)
+}
+
+// refresh refreshes the token unless another request has already replaced the stale one.
+func (t *Transport) refresh(ctx context.Context, stale *Token) (*Token, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token != stale && t.token.Valid() {
+		return t.token, nil
+	}
+	return t.refreshLocked(ctx)
+}
+
+func (t *Transport) refreshLocked(ctx context.Context) (*Token, error) {
+	if t.token == nil || t.token.RefreshToken == "" {
+		return nil, errors.New("token expired and cannot be refreshed: run login again")
+	}
+	tok, err := t.Config.Refresh(ctx, t.token.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if tok.RefreshToken == "" {
+		tok.RefreshToken = t.token.RefreshToken
+	}
+	if err := t.Store.Save(tok); err != nil {
+		return nil, err
+	}
+	t.token = tok
+	return tok, nil
+}
+
+// Authenticate does nothing because the Authorization header is set by RoundTrip.
+func (t *Transport) Authenticate(*http.Request) error {
+	return nil
+}
+
+// Redact returns s with the current access and refresh tokens replaced.
+func (t *Transport) Redact(s string) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token == nil {
+		return s
+	}
+	return backlog.BearerToken(t.token.RefreshToken).Redact(backlog.BearerToken(t.token.AccessToken).Redact(s))
+}
+
+func authorize(req *http.Request, tok *Token) *http.Request {
+	r := req.Clone(req.Context())
+	r.Header.Set("Authorization", "Bearer "+tok.AccessToken)
+	return r
+}
@@ -0,0 +1,159 @@
+package oauth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nekrassov01/backlog-utils/backlog"
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryStore struct {
+	token *Token
+	saved int
+}
+
+func (s *memoryStore) Load() (*Token, error) {
+	if s.token == nil {
+		return nil, ErrNoToken
+	}
+	return s.token, nil
+}
+
+func (s *memoryStore) Save(t *Token) error {
+	s.token = t
+	s.saved++
+	return nil
+}
+
+// newAPIServer returns a stand-in API endpoint that accepts only the specified access token.
+func newAPIServer(t *testing.T, accepted string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+accepted {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestTransport_RoundTrip(t *testing.T) {
+	type fields struct {
+		token *Token
+	}
+	type expected struct {
+		status  int
+		saved   int
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		accepted string
+		expected expected
+	}{
+		{
+			name:     "valid token",
+			fields:   fields{token: &Token{AccessToken: "access0", RefreshToken: "refresh"}},
+			accepted: "access0",
+			expected: expected{status: http.StatusOK, saved: 0, isError: false},
+		},
+		{
+			name:     "expired token",
+			fields:   fields{token: &Token{AccessToken: "access0", RefreshToken: "refresh", Expiry: time.Now().Add(-1 * time.Minute)}},
+			accepted: "access1",
+			expected: expected{status: http.StatusOK, saved: 1, isError: false},
+		},
+		{
+			name:     "rejected token",
+			fields:   fields{token: &Token{AccessToken: "revoked", RefreshToken: "refresh"}},
+			accepted: "access1",
+			expected: expected{status: http.StatusOK, saved: 1, isError: false},
+		},
+		{
+			name:     "rejected token without refresh token",
+			fields:   fields{token: &Token{AccessToken: "revoked"}},
+			accepted: "access1",
+			expected: expected{status: http.StatusUnauthorized, saved: 0, isError: false},
+		},
+		{
+			name:     "expired token without refresh token",
+			fields:   fields{token: &Token{AccessToken: "access0", Expiry: time.Now().Add(-1 * time.Minute)}},
+			accepted: "access0",
+			expected: expected{isError: true},
+		},
+		{
+			name:     "no token",
+			fields:   fields{token: nil},
+			accepted: "access0",
+			expected: expected{isError: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokenSrv, _ := newTokenServer(t)
+			apiSrv := newAPIServer(t, tt.accepted)
+			store := &memoryStore{token: tt.fields.token}
+			o := NewTransport(&Config{BaseURL: tokenSrv.URL, ClientID: "id", ClientSecret: "secret"}, store, nil)
+			body := "name=New+Name"
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodPatch, apiSrv.URL, strings.NewReader(body))
+			assert.NoError(t, err)
+			resp, err := o.RoundTrip(req)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, tt.expected.status, resp.StatusCode)
+			assert.Equal(t, tt.expected.saved, store.saved)
+			assert.Empty(t, req.Header.Get("Authorization"))
+			if resp.StatusCode == http.StatusOK {
+				b, _ := io.ReadAll(resp.Body)
+				assert.Equal(t, body, string(b))
+			}
+		})
+	}
+}
+
+func TestTransport_withClient(t *testing.T) {
+	tokenSrv, _ := newTokenServer(t)
+	apiSrv := newAPIServer(t, "access0")
+	o := NewTransport(
+		&Config{BaseURL: tokenSrv.URL, ClientID: "id", ClientSecret: "secret"},
+		&memoryStore{token: &Token{AccessToken: "access0", RefreshToken: "refresh"}},
+		backlog.NewRetryableTransport(1*time.Millisecond, 10*time.Millisecond, 2, 1),
+	)
+	c, err := backlog.NewClient(apiSrv.URL, "", backlog.WithTransport(o), backlog.WithAuthenticator(o))
+	assert.NoError(t, err)
+	_, err = backlog.Call[map[string]any](context.Background(), c, http.MethodGet, "/api/v2/space", nil, nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "Bearer REDACTED REDACTED", o.Redact("Bearer access0 refresh"))
+}
+
+func TestTransport_Redact(t *testing.T) {
+	o := NewTransport(&Config{}, &memoryStore{}, &errorRoundTripper{})
+	assert.Equal(t, "access0", o.Redact("access0"))
+
+	o.token = &Token{AccessToken: "access0"}
+	assert.Equal(t, "token: REDACTED", o.Redact("token: access0"))
+	assert.NoError(t, o.Authenticate(&http.Request{}))
+}
+
+type errorRoundTripper struct{}
+
+func (errorRoundTripper) RoundTrip(*http.Request) (*http.Response, error) {
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(&bytes.Buffer{})}, errors.New("error")
+}
@@ -0,0 +1,99 @@
+package backlog
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+var _ Observer = (*SlogObserver)(nil)
+
+// Observer receives the events of the requests sent by RetryableTransport, for example to log them.
+// Its methods may be called concurrently for different requests.
+type Observer interface {
+	// RequestStarted is called before the first attempt of a request.
+	RequestStarted(req *http.Request)
+
+	// RetryScheduled is called when an attempt failed and the request is sent again after a.Wait.
+	RetryScheduled(a *Attempt)
+
+	// GaveUp is called when the last allowed attempt failed with an error or status that would be retried.
+	GaveUp(a *Attempt)
+
+	// RequestFinished is called when RoundTrip returns.
+	RequestFinished(req *http.Request, r *Result)
+}
+
+// Attempt describes an attempt of a request sent by RetryableTransport.
+type Attempt struct {
+	Request *http.Request
+	N       int           // 1 for the first attempt
+	Status  int           // 0 if Err is not nil
+	Err     error         // error of the underlying transport
+	Wait    time.Duration // time before the next attempt if it is retried
+}
+
+// Reason returns why the attempt failed, which is the error or the status of the response.
+func (a *Attempt) Reason() string {
+	if a.Err != nil {
+		return a.Err.Error()
+	}
+	return fmt.Sprintf("%d %s", a.Status, http.StatusText(a.Status))
+}
+
+// Result describes the outcome of a request sent by RetryableTransport.
+type Result struct {
+	Status   int           // status of the returned response, 0 if Err is not nil
+	Err      error         // error returned by RoundTrip
+	Attempts int           // number of attempts sent to the underlying transport
+	Elapsed  time.Duration // time spent including the waits
+}
+
+// SlogObserver is an Observer that logs the events with slog.
+// Requests are logged at the debug level, and retries and give-ups as warnings.
+// Only the method and the path of the URL are logged so that the API key in the query is never written.
+type SlogObserver struct {
+	Logger *slog.Logger
+}
+
+// NewSlogObserver creates a new SlogObserver that logs to the specified logger.
+func NewSlogObserver(logger *slog.Logger) *SlogObserver {
+	return &SlogObserver{Logger: logger}
+}
+
+// RequestStarted logs the request at the debug level.
+func (o *SlogObserver) RequestStarted(req *http.Request) {
+	o.Logger.DebugContext(req.Context(), "request started", "method", req.Method, "path", req.URL.Path)
+}
+
+// RetryScheduled logs the retry as a warning with the reason and the wait time.
+func (o *SlogObserver) RetryScheduled(a *Attempt) {
+	o.Logger.WarnContext(a.Request.Context(), "retrying",
+		"method", a.Request.Method, "path", a.Request.URL.Path, "attempt", a.N, "reason", a.Reason(), "wait", a.Wait)
+}
+
+// GaveUp logs the last failed attempt as a warning.
+func (o *SlogObserver) GaveUp(a *Attempt) {
+	o.Logger.WarnContext(a.Request.Context(), "giving up",
+		"method", a.Request.Method, "path", a.Request.URL.Path, "attempt", a.N, "reason", a.Reason())
+}
+
+// RequestFinished logs the result of the request at the debug level.
+func (o *SlogObserver) RequestFinished(req *http.Request, r *Result) {
+	attrs := []any{"method", req.Method, "path", req.URL.Path}
+	if r.Err != nil {
+		attrs = append(attrs, "error", r.Err)
+	} else {
+		attrs = append(attrs, "status", r.Status)
+	}
+	attrs = append(attrs, "attempts", r.Attempts, "elapsed", r.Elapsed)
+	o.Logger.DebugContext(req.Context(), "request finished", attrs...)
+}
+
+type nopObserver struct{}
+
+func (nopObserver) RequestStarted(*http.Request)           {}
+func (nopObserver) RetryScheduled(*Attempt)                {}
+func (nopObserver) GaveUp(*Attempt)                        {}
+func (nopObserver) RequestFinished(*http.Request, *Result) {}
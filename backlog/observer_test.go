@@ -0,0 +1,119 @@
+package backlog
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttempt_Reason(t *testing.T) {
+	tests := []struct {
+		name     string
+		attempt  *Attempt
+		expected string
+	}{
+		{
+			name:     "status",
+			attempt:  &Attempt{N: 1, Status: http.StatusServiceUnavailable},
+			expected: "503 Service Unavailable",
+		},
+		{
+			name:     "error",
+			attempt:  &Attempt{N: 1, Err: io.ErrUnexpectedEOF},
+			expected: "unexpected EOF",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.attempt.Reason())
+		})
+	}
+}
+
+func TestSlogObserver(t *testing.T) {
+	newObserver := func(buf *bytes.Buffer, level slog.Level) *SlogObserver {
+		return NewSlogObserver(slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+			Level: level,
+			ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+				if a.Key == slog.TimeKey {
+					return slog.Attr{}
+				}
+				return a
+			},
+		})))
+	}
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "https://example.com/api/v2/wikis?apiKey=secret", nil)
+
+	type expected struct {
+		debug string
+		info  string
+	}
+	tests := []struct {
+		name     string
+		emit     func(o *SlogObserver)
+		expected expected
+	}{
+		{
+			name: "started",
+			emit: func(o *SlogObserver) { o.RequestStarted(req) },
+			expected: expected{
+				debug: "level=DEBUG msg=\"request started\" method=GET path=/api/v2/wikis\n",
+			},
+		},
+		{
+			name: "retry scheduled",
+			emit: func(o *SlogObserver) {
+				o.RetryScheduled(&Attempt{Request: req, N: 1, Status: http.StatusServiceUnavailable, Wait: 2 * time.Second})
+			},
+			expected: expected{
+				debug: "level=WARN msg=retrying method=GET path=/api/v2/wikis attempt=1 reason=\"503 Service Unavailable\" wait=2s\n",
+				info:  "level=WARN msg=retrying method=GET path=/api/v2/wikis attempt=1 reason=\"503 Service Unavailable\" wait=2s\n",
+			},
+		},
+		{
+			name: "gave up",
+			emit: func(o *SlogObserver) {
+				o.GaveUp(&Attempt{Request: req, N: 5, Err: syscall.ECONNRESET})
+			},
+			expected: expected{
+				debug: "level=WARN msg=\"giving up\" method=GET path=/api/v2/wikis attempt=5 reason=\"connection reset by peer\"\n",
+				info:  "level=WARN msg=\"giving up\" method=GET path=/api/v2/wikis attempt=5 reason=\"connection reset by peer\"\n",
+			},
+		},
+		{
+			name: "finished",
+			emit: func(o *SlogObserver) {
+				o.RequestFinished(req, &Result{Status: http.StatusOK, Attempts: 2, Elapsed: 1500 * time.Millisecond})
+			},
+			expected: expected{
+				debug: "level=DEBUG msg=\"request finished\" method=GET path=/api/v2/wikis status=200 attempts=2 elapsed=1.5s\n",
+			},
+		},
+		{
+			name: "failed",
+			emit: func(o *SlogObserver) {
+				o.RequestFinished(req, &Result{Err: context.Canceled, Attempts: 1, Elapsed: time.Second})
+			},
+			expected: expected{
+				debug: "level=DEBUG msg=\"request finished\" method=GET path=/api/v2/wikis error=\"context canceled\" attempts=1 elapsed=1s\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var debug, info bytes.Buffer
+			tt.emit(newObserver(&debug, slog.LevelDebug))
+			tt.emit(newObserver(&info, slog.LevelInfo))
+			assert.Equal(t, tt.expected.debug, debug.String())
+			assert.Equal(t, tt.expected.info, info.String())
+			assert.NotContains(t, debug.String(), "secret")
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package backlog
+
+import (
+	"context"
+	"iter"
+)
+
+// DefaultPageSize is the number of items fetched per page by default.
+// It is the maximum count accepted by the Backlog API.
+const DefaultPageSize = 100
+
+// PageParams represents the parameters for fetching a single page.
+// Offset is used by offset-based endpoints, and MinID and MaxID are used by ID-based endpoints.
+// Zero values mean that the parameter should not be sent.
+type PageParams struct {
+	Offset int
+	Count  int
+	MinID  int64
+	MaxID  int64
+}
+
+// PageFunc fetches a single page of items.
+type PageFunc[T any] func(ctx context.Context, p PageParams) ([]T, error)
+
+// PaginateOptions represents the options for paginating a list endpoint.
+type PaginateOptions struct {
+	// PageSize is the number of items fetched per page. It defaults to DefaultPageSize.
+	PageSize int
+	// MaxItems is the maximum number of items to yield. Zero means no limit.
+	MaxItems int
+	// Offset is the offset of the first page for offset-based endpoints.
+	Offset int
+	// Ascending makes ID-based pagination walk from older to newer items.
+	Ascending bool
+}
+
+// Paginate returns an iterator that lazily fetches pages from an offset-based endpoint.
+// The iteration stops when a page is shorter than the page size, when MaxItems is reached,
+// or when the context is canceled. An error is yielded once and ends the iteration.
+func Paginate[T any](ctx context.Context, fetch PageFunc[T], opts *PaginateOptions) iter.Seq2[T, error] {
+	size, maxItems := opts.pageSize(), opts.maxItems()
+	return func(yield func(T, error) bool) {
+		var zero T
+		n := 0
+		p := PageParams{Offset: opts.offset()}
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+			p.Count = size
+			if maxItems > 0 {
+				p.Count = min(size, maxItems-n)
+			}
+			items, err := fetch(ctx, p)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+				n++
+				if maxItems > 0 && n >= maxItems {
+					return
+				}
+			}
+			if len(items) < p.Count {
+				return
+			}
+			p.Offset += len(items)
+		}
+	}
+}
+
+// PaginateByID returns an iterator that lazily fetches pages from an ID-based endpoint.
+// The id function returns the ID of an item, which is used as the cursor of the next page.
+// By default the pages are walked from newer to older items using maxId,
+// and with Ascending from older to newer items using minId.
+// Items that were already yielded are skipped, so the cursor works whether the API treats it as inclusive or exclusive.
+func PaginateByID[T any](ctx context.Context, fetch PageFunc[T], id func(T) int64, opts *PaginateOptions) iter.Seq2[T, error] {
+	size, maxItems, asc := opts.pageSize(), opts.maxItems(), opts != nil && opts.Ascending
+	return func(yield func(T, error) bool) {
+		var zero T
+		n := 0
+		p := PageParams{}
+		var cursor int64
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+			p.Count = size
+			items, err := fetch(ctx, p)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			fresh := 0
+			for _, item := range items {
+				v := id(item)
+				if cursor != 0 && ((asc && v <= cursor) || (!asc && v >= cursor)) {
+					continue
+				}
+				if !yield(item, nil) {
+					return
+				}
+				fresh++
+				n++
+				if maxItems > 0 && n >= maxItems {
+					return
+				}
+				cursor = v
+			}
+			if fresh == 0 || len(items) < p.Count {
+				return
+			}
+			if asc {
+				p.MinID = cursor
+			} else {
+				p.MaxID = cursor
+			}
+		}
+	}
+}
+
+func (o *PaginateOptions) pageSize() int {
+	if o == nil || o.PageSize <= 0 {
+		return DefaultPageSize
+	}
+	return o.PageSize
+}
+
+func (o *PaginateOptions) maxItems() int {
+	if o == nil || o.MaxItems < 0 {
+		return 0
+	}
+	return o.MaxItems
+}
+
+func (o *PaginateOptions) offset() int {
+	if o == nil || o.Offset < 0 {
+		return 0
+	}
+	return o.Offset
+}
@@ -0,0 +1,296 @@
+package backlog
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func offsetFetcher(total int, calls *[]PageParams) PageFunc[int] {
+	return func(_ context.Context, p PageParams) ([]int, error) {
+		*calls = append(*calls, p)
+		items := make([]int, 0, p.Count)
+		for i := p.Offset; i < total && len(items) < p.Count; i++ {
+			items = append(items, i+1)
+		}
+		return items, nil
+	}
+}
+
+// idFetcher simulates an endpoint that returns items in descending order by default,
+// and treats minId and maxId as inclusive bounds.
+func idFetcher(total int64, asc bool, calls *[]PageParams) PageFunc[int64] {
+	return func(_ context.Context, p PageParams) ([]int64, error) {
+		*calls = append(*calls, p)
+		items := make([]int64, 0, p.Count)
+		if asc {
+			start := max(p.MinID, 1)
+			for i := start; i <= total && len(items) < p.Count; i++ {
+				items = append(items, i)
+			}
+			return items, nil
+		}
+		start := total
+		if p.MaxID != 0 {
+			start = p.MaxID
+		}
+		for i := start; i >= 1 && len(items) < p.Count; i-- {
+			items = append(items, i)
+		}
+		return items, nil
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	type args struct {
+		total int
+		opts  *PaginateOptions
+	}
+	type expected struct {
+		value []int
+		calls []PageParams
+	}
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "basic",
+			args: args{
+				total: 5,
+				opts:  &PaginateOptions{PageSize: 2},
+			},
+			expected: expected{
+				value: []int{1, 2, 3, 4, 5},
+				calls: []PageParams{
+					{Offset: 0, Count: 2},
+					{Offset: 2, Count: 2},
+					{Offset: 4, Count: 2},
+				},
+			},
+		},
+		{
+			name: "exact multiple of page size",
+			args: args{
+				total: 4,
+				opts:  &PaginateOptions{PageSize: 2},
+			},
+			expected: expected{
+				value: []int{1, 2, 3, 4},
+				calls: []PageParams{
+					{Offset: 0, Count: 2},
+					{Offset: 2, Count: 2},
+					{Offset: 4, Count: 2},
+				},
+			},
+		},
+		{
+			name: "max items",
+			args: args{
+				total: 10,
+				opts:  &PaginateOptions{PageSize: 4, MaxItems: 6},
+			},
+			expected: expected{
+				value: []int{1, 2, 3, 4, 5, 6},
+				calls: []PageParams{
+					{Offset: 0, Count: 4},
+					{Offset: 4, Count: 2},
+				},
+			},
+		},
+		{
+			name: "offset",
+			args: args{
+				total: 5,
+				opts:  &PaginateOptions{PageSize: 10, Offset: 3},
+			},
+			expected: expected{
+				value: []int{4, 5},
+				calls: []PageParams{
+					{Offset: 3, Count: 10},
+				},
+			},
+		},
+		{
+			name: "nil options",
+			args: args{
+				total: 3,
+				opts:  nil,
+			},
+			expected: expected{
+				value: []int{1, 2, 3},
+				calls: []PageParams{
+					{Offset: 0, Count: DefaultPageSize},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []PageParams
+			actual := []int{}
+			for v, err := range Paginate(context.Background(), offsetFetcher(tt.args.total, &calls), tt.args.opts) {
+				assert.NoError(t, err)
+				actual = append(actual, v)
+			}
+			assert.Equal(t, tt.expected.value, actual)
+			assert.Equal(t, tt.expected.calls, calls)
+		})
+	}
+}
+
+func TestPaginate_Error(t *testing.T) {
+	fetchErr := errors.New("fetch error")
+	n := 0
+	fetch := func(_ context.Context, p PageParams) ([]int, error) {
+		n++
+		if p.Offset > 0 {
+			return nil, fetchErr
+		}
+		return []int{1, 2}, nil
+	}
+	var values []int
+	var errs []error
+	for v, err := range Paginate(context.Background(), fetch, &PaginateOptions{PageSize: 2}) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		values = append(values, v)
+	}
+	assert.Equal(t, []int{1, 2}, values)
+	assert.Equal(t, []error{fetchErr}, errs)
+	assert.Equal(t, 2, n)
+}
+
+func TestPaginate_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var calls []PageParams
+	var values []int
+	var errs []error
+	for v, err := range Paginate(ctx, offsetFetcher(10, &calls), &PaginateOptions{PageSize: 2}) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		values = append(values, v)
+		cancel()
+	}
+	assert.Equal(t, []int{1, 2}, values)
+	assert.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], context.Canceled)
+	assert.Len(t, calls, 1)
+}
+
+func TestPaginate_Break(t *testing.T) {
+	var calls []PageParams
+	for v := range Paginate(context.Background(), offsetFetcher(10, &calls), &PaginateOptions{PageSize: 2}) {
+		if v == 3 {
+			break
+		}
+	}
+	assert.Len(t, calls, 2)
+}
+
+func TestPaginateByID(t *testing.T) {
+	type args struct {
+		total int64
+		opts  *PaginateOptions
+	}
+	type expected struct {
+		value []int64
+		calls []PageParams
+	}
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "descending",
+			args: args{
+				total: 5,
+				opts:  &PaginateOptions{PageSize: 3},
+			},
+			expected: expected{
+				value: []int64{5, 4, 3, 2, 1},
+				calls: []PageParams{
+					{Count: 3},
+					{Count: 3, MaxID: 3},
+					{Count: 3, MaxID: 1},
+				},
+			},
+		},
+		{
+			name: "ascending",
+			args: args{
+				total: 4,
+				opts:  &PaginateOptions{PageSize: 3, Ascending: true},
+			},
+			expected: expected{
+				value: []int64{1, 2, 3, 4},
+				calls: []PageParams{
+					{Count: 3},
+					{Count: 3, MinID: 3},
+				},
+			},
+		},
+		{
+			name: "max items",
+			args: args{
+				total: 10,
+				opts:  &PaginateOptions{PageSize: 3, MaxItems: 4},
+			},
+			expected: expected{
+				value: []int64{10, 9, 8, 7},
+				calls: []PageParams{
+					{Count: 3},
+					{Count: 3, MaxID: 8},
+				},
+			},
+		},
+		{
+			name: "empty",
+			args: args{
+				total: 0,
+				opts:  nil,
+			},
+			expected: expected{
+				value: []int64{},
+				calls: []PageParams{
+					{Count: DefaultPageSize},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []PageParams
+			asc := tt.args.opts != nil && tt.args.opts.Ascending
+			actual := []int64{}
+			id := func(v int64) int64 { return v }
+			for v, err := range PaginateByID(context.Background(), idFetcher(tt.args.total, asc, &calls), id, tt.args.opts) {
+				assert.NoError(t, err)
+				actual = append(actual, v)
+			}
+			assert.Equal(t, tt.expected.value, actual)
+			assert.Equal(t, tt.expected.calls, calls)
+		})
+	}
+}
+
+func TestPaginateByID_Error(t *testing.T) {
+	fetchErr := errors.New("fetch error")
+	fetch := func(context.Context, PageParams) ([]int64, error) {
+		return nil, fetchErr
+	}
+	var errs []error
+	for _, err := range PaginateByID(context.Background(), fetch, func(v int64) int64 { return v }, nil) {
+		errs = append(errs, err)
+	}
+	assert.Equal(t, []error{fetchErr}, errs)
+}
@@ -0,0 +1,90 @@
+package backlog
+
+import (
+	"context"
+	"iter"
+	"sync"
+)
+
+// Parallel returns an iterator that calls fn for each item with at most concurrency goroutines.
+// Results are yielded in the order of items regardless of the order in which the calls finish.
+// An error returned by fn is yielded with its result and the iteration continues with the next item.
+// When the context is canceled, no more calls are started, and the results of the calls in flight are
+// still yielded once they return, followed by the context error for each item that was not started.
+// Breaking out of the iteration cancels the context passed to the calls in flight and waits for them to return.
+func Parallel[T, R any](ctx context.Context, items []T, concurrency int, fn func(context.Context, T) (R, error)) iter.Seq2[R, error] {
+	return func(yield func(R, error) bool) {
+		if len(items) == 0 {
+			return
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		var wg sync.WaitGroup
+		defer func() {
+			cancel()
+			wg.Wait()
+		}()
+
+		type result struct {
+			value R
+			err   error
+		}
+		results := make([]chan result, len(items))
+		for i := range results {
+			results[i] = make(chan result, 1)
+		}
+
+		jobs := make(chan int)
+		go func() {
+			defer close(jobs)
+			for i := range items {
+				select {
+				case jobs <- i:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+
+		for range min(max(concurrency, 1), len(items)) {
+			wg.Go(func() {
+				for i := range jobs {
+					// A job received after cancellation is left without a result as it was not started.
+					if ctx.Err() != nil {
+						continue
+					}
+					v, err := fn(ctx, items[i])
+					results[i] <- result{value: v, err: err}
+				}
+			})
+		}
+
+		var zero R
+		for i := range items {
+			select {
+			case r := <-results[i]:
+				if !yield(r.value, r.err) {
+					return
+				}
+				continue
+			case <-ctx.Done():
+			}
+
+			// Once the workers return, every started item has its result and the others have none.
+			wg.Wait()
+			for _, ch := range results[i:] {
+				select {
+				case r := <-ch:
+					if !yield(r.value, r.err) {
+						return
+					}
+				default:
+					if !yield(zero, ctx.Err()) {
+						return
+					}
+				}
+			}
+			return
+		}
+	}
+}
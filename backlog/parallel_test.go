@@ -0,0 +1,159 @@
+package backlog
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParallel(t *testing.T) {
+	type args struct {
+		items       []int
+		concurrency int
+	}
+	type expected struct {
+		values []int
+		errs   int
+	}
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "basic",
+			args: args{
+				items:       []int{1, 2, 3, 4, 5, 6},
+				concurrency: 3,
+			},
+			expected: expected{
+				values: []int{10, 20, 0, 40, 50, 0},
+				errs:   2,
+			},
+		},
+		{
+			name: "sequential",
+			args: args{
+				items:       []int{1, 2},
+				concurrency: 0,
+			},
+			expected: expected{
+				values: []int{10, 20},
+				errs:   0,
+			},
+		},
+		{
+			name: "empty",
+			args: args{
+				items:       nil,
+				concurrency: 3,
+			},
+			expected: expected{
+				values: nil,
+				errs:   0,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var active, peak atomic.Int32
+			fn := func(_ context.Context, n int) (int, error) {
+				cur := active.Add(1)
+				defer active.Add(-1)
+				for {
+					p := peak.Load()
+					if cur <= p || peak.CompareAndSwap(p, cur) {
+						break
+					}
+				}
+				// Later items finish first to check that the order is preserved.
+				time.Sleep(time.Duration(10-n) * time.Millisecond)
+				if n%3 == 0 {
+					return 0, errors.New("error")
+				}
+				return n * 10, nil
+			}
+			var values []int
+			errs := 0
+			for v, err := range Parallel(context.Background(), tt.args.items, tt.args.concurrency, fn) {
+				if err != nil {
+					errs++
+				}
+				values = append(values, v)
+			}
+			assert.Equal(t, tt.expected.values, values)
+			assert.Equal(t, tt.expected.errs, errs)
+			assert.LessOrEqual(t, int(peak.Load()), max(tt.args.concurrency, 1))
+		})
+	}
+}
+
+func TestParallel_Break(t *testing.T) {
+	var calls atomic.Int32
+	fn := func(ctx context.Context, n int) (int, error) {
+		calls.Add(1)
+		if n > 0 {
+			<-ctx.Done()
+		}
+		return n, ctx.Err()
+	}
+	items := make([]int, 100)
+	for i := range items {
+		items[i] = i
+	}
+	for v := range Parallel(context.Background(), items, 2, fn) {
+		_ = v
+		break
+	}
+	assert.Less(t, int(calls.Load()), len(items))
+}
+
+func TestParallel_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	fn := func(_ context.Context, n int) (int, error) {
+		return n, nil
+	}
+	var last error
+	for _, err := range Parallel(ctx, []int{1, 2, 3}, 2, fn) {
+		last = err
+	}
+	assert.ErrorIs(t, last, context.Canceled)
+}
+
+func TestParallel_CanceledInFlight(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var started sync.WaitGroup
+	started.Add(2)
+	var calls atomic.Int32
+	fn := func(ctx context.Context, n int) (int, error) {
+		calls.Add(1)
+		started.Done()
+		<-ctx.Done()
+		// The call in flight completes regardless of the cancellation, like a request already processed.
+		return n, nil
+	}
+	go func() {
+		started.Wait()
+		cancel()
+	}()
+	var values []int
+	var errs []error
+	for v, err := range Parallel(ctx, []int{1, 2, 3, 4, 5}, 2, fn) {
+		values = append(values, v)
+		errs = append(errs, err)
+	}
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Equal(t, []int{1, 2, 0, 0, 0}, values)
+	assert.NoError(t, errs[0])
+	assert.NoError(t, errs[1])
+	for _, err := range errs[2:] {
+		assert.ErrorIs(t, err, context.Canceled)
+	}
+}
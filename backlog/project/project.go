@@ -0,0 +1,53 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/nekrassov01/backlog-utils/backlog"
+)
+
+// Client represents a Backlog project client.
+type Client struct {
+	*backlog.Client
+}
+
+// Project represents a Backlog project.
+type Project struct {
+	ID         int64  `json:"id"`
+	ProjectKey string `json:"projectKey"`
+	Name       string `json:"name"`
+	Archived   bool   `json:"archived"`
+}
+
+// NewClient creates a new Backlog project client.
+func NewClient(url, apiKey string, opts ...backlog.ClientOption) (*Client, error) {
+	o, err := backlog.NewClient(url, apiKey, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{o}, nil
+}
+
+// Get returns a project by the specified project ID or key.
+func (c *Client) Get(idOrKey string) (*Project, error) {
+	return c.GetContext(context.Background(), idOrKey)
+}
+
+// GetContext is like Get but uses the specified context for the request.
+func (c *Client) GetContext(ctx context.Context, idOrKey string) (*Project, error) {
+	if idOrKey == "" {
+		return nil, errors.New("empty project key")
+	}
+
+	path := fmt.Sprintf("/api/v2/projects/%s", url.PathEscape(idOrKey))
+	project, err := backlog.Call[*Project](ctx, c.Client, http.MethodGet, path, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	return project, nil
+}
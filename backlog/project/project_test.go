@@ -0,0 +1,172 @@
+package project
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/nekrassov01/backlog-utils/backlog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewClient(t *testing.T) {
+	type args struct {
+		url    string
+		apiKey string
+		opts   []backlog.ClientOption
+	}
+	type expected struct {
+		value   *Client
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "basic",
+			args: args{
+				url:    "https://example.com",
+				apiKey: "dummy",
+				opts: []backlog.ClientOption{
+					backlog.WithWriter(io.Discard),
+					backlog.WithTransport(http.DefaultTransport),
+				},
+			},
+			expected: expected{
+				value: &Client{
+					&backlog.Client{
+						Writer:  io.Discard,
+						BaseURL: "https://example.com",
+						APIKey:  "dummy",
+						HTTPClient: &http.Client{
+							Transport: http.DefaultTransport,
+						},
+					},
+				},
+				isError: false,
+			},
+		},
+		{
+			name: "empty url",
+			args: args{
+				url:    "",
+				apiKey: "dummy",
+				opts:   nil,
+			},
+			expected: expected{
+				value:   nil,
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := NewClient(tt.args.url, tt.args.apiKey, tt.args.opts...)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.value, actual)
+		})
+	}
+}
+
+func TestProject_Get(t *testing.T) {
+	type args struct {
+		idOrKey string
+	}
+	type expected struct {
+		value   *Project
+		isError bool
+	}
+	type mock struct {
+		status int
+		body   string
+	}
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+		mock     mock
+	}{
+		{
+			name: "basic",
+			args: args{
+				idOrKey: "TEST",
+			},
+			expected: expected{
+				value: &Project{
+					ID:         123,
+					ProjectKey: "TEST",
+					Name:       "Test Project",
+				},
+				isError: false,
+			},
+			mock: mock{
+				status: 200,
+				body:   `{"id":123,"projectKey":"TEST","name":"Test Project","archived":false}`,
+			},
+		},
+		{
+			name: "empty project key",
+			args: args{
+				idOrKey: "",
+			},
+			expected: expected{
+				value:   nil,
+				isError: true,
+			},
+			mock: mock{
+				status: 0,
+				body:   "",
+			},
+		},
+		{
+			name: "api error",
+			args: args{
+				idOrKey: "TEST",
+			},
+			expected: expected{
+				value:   nil,
+				isError: true,
+			},
+			mock: mock{
+				status: 404,
+				body:   `{"errors":[{"message":"No project.","code":6}]}`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Client{
+				Client: &backlog.Client{
+					Writer:     io.Discard,
+					BaseURL:    "https://example.com",
+					APIKey:     "dummy",
+					HTTPClient: &http.Client{},
+				},
+			}
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			if tt.mock.status != 0 {
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					fmt.Sprintf("%s/api/v2/projects/%s?apiKey=%s", o.BaseURL, tt.args.idOrKey, o.APIKey),
+					httpmock.NewStringResponder(tt.mock.status, tt.mock.body),
+				)
+			}
+			actual, err := o.Get(tt.args.idOrKey)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.value, actual)
+		})
+	}
+}
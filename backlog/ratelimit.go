@@ -0,0 +1,105 @@
+package backlog
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	limitHeaderKey     = "X-Ratelimit-Limit"
+	remainingHeaderKey = "X-Ratelimit-Remaining"
+	resetHeaderKey     = "X-Ratelimit-Reset"
+)
+
+// RateLimit represents the rate limit status reported by the Backlog API.
+type RateLimit struct {
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	Reset     time.Time `json:"reset"`
+}
+
+// ParseRateLimit returns the rate limit status from the X-RateLimit-* response headers.
+// It returns nil if the headers are missing or invalid.
+func ParseRateLimit(h http.Header) *RateLimit {
+	limit, err := strconv.Atoi(h.Get(limitHeaderKey))
+	if err != nil {
+		return nil
+	}
+	remaining, err := strconv.Atoi(h.Get(remainingHeaderKey))
+	if err != nil {
+		return nil
+	}
+	reset, err := strconv.ParseInt(h.Get(resetHeaderKey), 10, 64)
+	if err != nil {
+		return nil
+	}
+	return &RateLimit{
+		Limit:     limit,
+		Remaining: remaining,
+		Reset:     time.Unix(reset, 0),
+	}
+}
+
+// RateLimitStatus represents the rate limits of each category for the authenticated user.
+type RateLimitStatus struct {
+	Read   *RateLimit `json:"read"`
+	Update *RateLimit `json:"update"`
+	Search *RateLimit `json:"search"`
+	Icon   *RateLimit `json:"icon"`
+}
+
+// Category returns the rate limit of the category, or nil if it is unknown.
+func (s *RateLimitStatus) Category(c RateLimitCategory) *RateLimit {
+	switch c {
+	case RateLimitRead:
+		return s.Read
+	case RateLimitUpdate:
+		return s.Update
+	case RateLimitSearch:
+		return s.Search
+	case RateLimitIcon:
+		return s.Icon
+	}
+	return nil
+}
+
+// rateLimitResponse represents the response of the rate limit API, which reports resets in Unix seconds.
+type rateLimitResponse struct {
+	RateLimit map[RateLimitCategory]struct {
+		Limit     int   `json:"limit"`
+		Remaining int   `json:"remaining"`
+		Reset     int64 `json:"reset"`
+	} `json:"rateLimit"`
+}
+
+// GetRateLimit returns the rate limits of the space for the authenticated user.
+func (c *Client) GetRateLimit() (*RateLimitStatus, error) {
+	return c.GetRateLimitContext(context.Background())
+}
+
+// GetRateLimitContext is like GetRateLimit but uses the specified context for the request.
+func (c *Client) GetRateLimitContext(ctx context.Context) (*RateLimitStatus, error) {
+	resp, err := Call[*rateLimitResponse](ctx, c, http.MethodGet, "/api/v2/rateLimit", nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rate limit: %w", err)
+	}
+
+	s := &RateLimitStatus{}
+	for category, v := range resp.RateLimit {
+		rl := &RateLimit{Limit: v.Limit, Remaining: v.Remaining, Reset: time.Unix(v.Reset, 0)}
+		switch category {
+		case RateLimitRead:
+			s.Read = rl
+		case RateLimitUpdate:
+			s.Update = rl
+		case RateLimitSearch:
+			s.Search = rl
+		case RateLimitIcon:
+			s.Icon = rl
+		}
+	}
+	return s, nil
+}
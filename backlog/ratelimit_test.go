@@ -0,0 +1,171 @@
+package backlog
+
+import (
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	type expected struct {
+		value *RateLimit
+	}
+	tests := []struct {
+		name     string
+		header   http.Header
+		expected expected
+	}{
+		{
+			name: "basic",
+			header: http.Header{
+				limitHeaderKey:     {"600"},
+				remainingHeaderKey: {"599"},
+				resetHeaderKey:     {"1743465600"},
+			},
+			expected: expected{
+				value: &RateLimit{
+					Limit:     600,
+					Remaining: 599,
+					Reset:     time.Unix(1743465600, 0),
+				},
+			},
+		},
+		{
+			name:   "empty",
+			header: http.Header{},
+			expected: expected{
+				value: nil,
+			},
+		},
+		{
+			name: "invalid limit",
+			header: http.Header{
+				limitHeaderKey:     {"invalid"},
+				remainingHeaderKey: {"599"},
+				resetHeaderKey:     {"1743465600"},
+			},
+			expected: expected{
+				value: nil,
+			},
+		},
+		{
+			name: "invalid remaining",
+			header: http.Header{
+				limitHeaderKey:     {"600"},
+				remainingHeaderKey: {"invalid"},
+				resetHeaderKey:     {"1743465600"},
+			},
+			expected: expected{
+				value: nil,
+			},
+		},
+		{
+			name: "invalid reset",
+			header: http.Header{
+				limitHeaderKey:     {"600"},
+				remainingHeaderKey: {"599"},
+				resetHeaderKey:     {"invalid"},
+			},
+			expected: expected{
+				value: nil,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := ParseRateLimit(tt.header)
+			assert.Equal(t, tt.expected.value, actual)
+		})
+	}
+}
+
+func TestClient_GetRateLimit(t *testing.T) {
+	type expected struct {
+		value   *RateLimitStatus
+		isError bool
+	}
+	type mock struct {
+		status int
+		body   string
+	}
+	tests := []struct {
+		name     string
+		expected expected
+		mock     mock
+	}{
+		{
+			name: "basic",
+			expected: expected{
+				value: &RateLimitStatus{
+					Read:   &RateLimit{Limit: 600, Remaining: 599, Reset: time.Unix(1743465600, 0)},
+					Update: &RateLimit{Limit: 150, Remaining: 150, Reset: time.Unix(1743465660, 0)},
+					Search: &RateLimit{Limit: 150, Remaining: 10, Reset: time.Unix(1743465600, 0)},
+					Icon:   &RateLimit{Limit: 60, Remaining: 60, Reset: time.Unix(1743465600, 0)},
+				},
+				isError: false,
+			},
+			mock: mock{
+				status: 200,
+				body: `{"rateLimit":{` +
+					`"read":{"limit":600,"remaining":599,"reset":1743465600},` +
+					`"update":{"limit":150,"remaining":150,"reset":1743465660},` +
+					`"search":{"limit":150,"remaining":10,"reset":1743465600},` +
+					`"icon":{"limit":60,"remaining":60,"reset":1743465600}}}`,
+			},
+		},
+		{
+			name: "missing category",
+			expected: expected{
+				value: &RateLimitStatus{
+					Read: &RateLimit{Limit: 600, Remaining: 599, Reset: time.Unix(1743465600, 0)},
+				},
+				isError: false,
+			},
+			mock: mock{
+				status: 200,
+				body:   `{"rateLimit":{"read":{"limit":600,"remaining":599,"reset":1743465600}}}`,
+			},
+		},
+		{
+			name: "api error",
+			expected: expected{
+				value:   nil,
+				isError: true,
+			},
+			mock: mock{
+				status: 401,
+				body:   `{"errors":[{"message":"Authentication failure.","code":11}]}`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Client{
+				Writer:     io.Discard,
+				BaseURL:    "https://example.com",
+				APIKey:     "dummy",
+				HTTPClient: &http.Client{},
+			}
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				o.BaseURL+"/api/v2/rateLimit?apiKey=dummy",
+				httpmock.NewStringResponder(tt.mock.status, tt.mock.body),
+			)
+			actual, err := o.GetRateLimit()
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.value, actual)
+			assert.Equal(t, actual.Read, actual.Category(RateLimitRead))
+			assert.Equal(t, actual.Icon, actual.Category(RateLimitIcon))
+		})
+	}
+}
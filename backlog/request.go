@@ -0,0 +1,84 @@
+package backlog
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// NewRequest creates a new Backlog API request for the specified path.
+// The query is sent in the URL, the form, if not nil, is sent as an URL-encoded body,
+// and the request is authenticated by the authenticator of the client.
+func (c *Client) NewRequest(ctx context.Context, method, path string, query, form url.Values) (*http.Request, error) {
+	u, err := url.Parse(strings.TrimSuffix(c.BaseURL, "/") + path)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(query) > 0 {
+		u.RawQuery = query.Encode()
+	}
+
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if err := c.authenticator().Authenticate(req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// Do sends an API request and decodes the JSON response body into v.
+// If v is nil or the response body is empty, the body is discarded.
+// A response with a non-2xx status code is returned as an *APIError.
+// Credentials are redacted from the returned errors.
+func (c *Client) Do(req *http.Request, v any) error {
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return redactError(c.authenticator(), err)
+	}
+
+	//nolint:errcheck
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return newAPIError(req, resp)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if v == nil || len(body) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(body, v)
+}
+
+// Call sends an API request and returns the JSON response body decoded as T.
+func Call[T any](ctx context.Context, c *Client, method, path string, query, form url.Values) (T, error) {
+	var v T
+	req, err := c.NewRequest(ctx, method, path, query, form)
+	if err != nil {
+		return v, err
+	}
+	if err := c.Do(req, &v); err != nil {
+		return v, err
+	}
+	return v, nil
+}
@@ -0,0 +1,311 @@
+package backlog
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_NewRequest(t *testing.T) {
+	type args struct {
+		method string
+		path   string
+		query  url.Values
+		form   url.Values
+	}
+	type expected struct {
+		url         string
+		contentType string
+		body        string
+		isError     bool
+	}
+	tests := []struct {
+		name     string
+		baseURL  string
+		args     args
+		expected expected
+	}{
+		{
+			name:    "basic",
+			baseURL: "https://example.com",
+			args: args{
+				method: http.MethodGet,
+				path:   "/api/v2/wikis",
+				query:  url.Values{"projectIdOrKey": {"TEST"}},
+				form:   nil,
+			},
+			expected: expected{
+				url:         "https://example.com/api/v2/wikis?projectIdOrKey=TEST&apiKey=dummy",
+				contentType: "",
+				body:        "",
+				isError:     false,
+			},
+		},
+		{
+			name:    "trailing slash",
+			baseURL: "https://example.com/",
+			args: args{
+				method: http.MethodGet,
+				path:   "/api/v2/wikis/1",
+				query:  nil,
+				form:   nil,
+			},
+			expected: expected{
+				url:         "https://example.com/api/v2/wikis/1?apiKey=dummy",
+				contentType: "",
+				body:        "",
+				isError:     false,
+			},
+		},
+		{
+			name:    "form",
+			baseURL: "https://example.com",
+			args: args{
+				method: http.MethodPatch,
+				path:   "/api/v2/wikis/1",
+				query:  nil,
+				form:   url.Values{"name": {"New Name"}},
+			},
+			expected: expected{
+				url:         "https://example.com/api/v2/wikis/1?apiKey=dummy",
+				contentType: "application/x-www-form-urlencoded",
+				body:        "name=New+Name",
+				isError:     false,
+			},
+		},
+		{
+			name:    "invalid url",
+			baseURL: "://example.com",
+			args: args{
+				method: http.MethodGet,
+				path:   "/api/v2/wikis",
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name:    "invalid method",
+			baseURL: "https://example.com",
+			args: args{
+				method: "INVALID METHOD",
+				path:   "/api/v2/wikis",
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{
+				BaseURL:    tt.baseURL,
+				APIKey:     "dummy",
+				HTTPClient: newDefaultHTTPClient(),
+			}
+			req, err := c.NewRequest(context.Background(), tt.args.method, tt.args.path, tt.args.query, tt.args.form)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.url, req.URL.String())
+			assert.Equal(t, tt.expected.contentType, req.Header.Get("Content-Type"))
+			if req.Body != nil {
+				b, err := io.ReadAll(req.Body)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.body, string(b))
+			}
+		})
+	}
+}
+
+func TestClient_Do(t *testing.T) {
+	type mock struct {
+		status int
+		body   string
+	}
+	type expected struct {
+		value     map[string]any
+		isError   bool
+		errStatus int
+	}
+	tests := []struct {
+		name     string
+		mock     mock
+		expected expected
+	}{
+		{
+			name: "basic",
+			mock: mock{
+				status: 200,
+				body:   `{"id":1}`,
+			},
+			expected: expected{
+				value:   map[string]any{"id": float64(1)},
+				isError: false,
+			},
+		},
+		{
+			name: "created",
+			mock: mock{
+				status: 201,
+				body:   `{"id":1}`,
+			},
+			expected: expected{
+				value:   map[string]any{"id": float64(1)},
+				isError: false,
+			},
+		},
+		{
+			name: "empty body",
+			mock: mock{
+				status: 204,
+				body:   "",
+			},
+			expected: expected{
+				value:   nil,
+				isError: false,
+			},
+		},
+		{
+			name: "api error",
+			mock: mock{
+				status: 404,
+				body:   `{"errors":[{"message":"No wiki.","code":6}]}`,
+			},
+			expected: expected{
+				value:     nil,
+				isError:   true,
+				errStatus: 404,
+			},
+		},
+		{
+			name: "invalid response",
+			mock: mock{
+				status: 200,
+				body:   `{"id":}`,
+			},
+			expected: expected{
+				value:   nil,
+				isError: true,
+			},
+		},
+		{
+			name: "no responder",
+			mock: mock{
+				status: 0,
+			},
+			expected: expected{
+				value:   nil,
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{
+				BaseURL:    "https://example.com",
+				APIKey:     "dummy",
+				HTTPClient: &http.Client{},
+			}
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			if tt.mock.status != 0 {
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					"https://example.com/api/v2/test?apiKey=dummy",
+					httpmock.NewStringResponder(tt.mock.status, tt.mock.body).HeaderSet(http.Header{
+						limitHeaderKey:     {"150"},
+						remainingHeaderKey: {"0"},
+						resetHeaderKey:     {"1743465600"},
+					}),
+				)
+			}
+			req, err := c.NewRequest(context.Background(), http.MethodGet, "/api/v2/test", nil, nil)
+			assert.NoError(t, err)
+			var actual map[string]any
+			err = c.Do(req, &actual)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				if tt.expected.errStatus != 0 {
+					var apiErr *APIError
+					assert.True(t, errors.As(err, &apiErr))
+					assert.Equal(t, tt.expected.errStatus, apiErr.StatusCode)
+					assert.Equal(t, http.MethodGet, apiErr.Method)
+					assert.Equal(t, "/api/v2/test", apiErr.Path)
+					assert.Equal(t, []Error{{Message: "No wiki.", Code: 6}}, apiErr.Errors)
+					assert.Equal(t, &RateLimit{Limit: 150, Remaining: 0, Reset: time.Unix(1743465600, 0)}, apiErr.RateLimit)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.value, actual)
+		})
+	}
+}
+
+func TestCall(t *testing.T) {
+	type item struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	}
+	type expected struct {
+		value   []*item
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		baseURL  string
+		expected expected
+	}{
+		{
+			name:    "basic",
+			baseURL: "https://example.com",
+			expected: expected{
+				value: []*item{
+					{ID: 1, Name: "first"},
+				},
+				isError: false,
+			},
+		},
+		{
+			name:    "invalid url",
+			baseURL: "://example.com",
+			expected: expected{
+				value:   nil,
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{
+				BaseURL:    tt.baseURL,
+				APIKey:     "dummy",
+				HTTPClient: &http.Client{},
+			}
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				"https://example.com/api/v2/items?apiKey=dummy",
+				httpmock.NewStringResponder(200, `[{"id":1,"name":"first"}]`),
+			)
+			actual, err := Call[[]*item](context.Background(), c, http.MethodGet, "/api/v2/items", nil, nil)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.value, actual)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package backlog
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const retryAfterHeaderKey = "Retry-After"
+
+type idempotentKey struct{}
+
+// WithIdempotent returns a context that marks the requests made with it as idempotent,
+// so that they are retried after network errors even if their method is not, such as
+// a PATCH that sets fields to absolute values.
+func WithIdempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentKey{}, true)
+}
+
+// RetryPolicy decides which failed requests are retried by RetryableTransport.
+type RetryPolicy struct {
+	// Statuses are the response status codes that are retried.
+	Statuses []int `json:"statuses"`
+
+	// NetworkErrors makes transient network errors retried, as reported by IsTransientNetworkError.
+	// A request that may have reached the server is retried only if it is idempotent,
+	// while a request whose connection could not be established is retried regardless of its method.
+	NetworkErrors bool `json:"networkErrors"`
+
+	// IdempotentOnly restricts retries of the statuses to idempotent requests, so that a request that
+	// may have been processed is never repeated. 429 responses are still retried because they are rejected
+	// before processing.
+	IdempotentOnly bool `json:"idempotentOnly"`
+}
+
+// DefaultRetryPolicy returns the policy that retries 429 and transient 5xx responses of any method,
+// and transient network errors of idempotent requests.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		NetworkErrors: true,
+		Statuses: []int{
+			http.StatusTooManyRequests,     // 429
+			http.StatusInternalServerError, // 500
+			http.StatusBadGateway,          // 502
+			http.StatusServiceUnavailable,  // 503
+			http.StatusGatewayTimeout,      // 504
+		},
+	}
+}
+
+// Retryable reports whether the request should be retried after it got resp or err.
+// Errors caused by the end of the request context are never retried.
+func (p *RetryPolicy) Retryable(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		if req.Context().Err() != nil || !p.NetworkErrors || !IsTransientNetworkError(err) {
+			return false
+		}
+		return isIdempotent(req) || isDialError(err)
+	}
+	if !slices.Contains(p.Statuses, resp.StatusCode) {
+		return false
+	}
+	return !p.IdempotentOnly || isIdempotent(req) || resp.StatusCode == http.StatusTooManyRequests
+}
+
+// IsTransientNetworkError reports whether err is a network error that may not recur,
+// such as a timeout, a reset or refused connection, or a connection closed unexpectedly.
+func IsTransientNetworkError(err error) bool {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+}
+
+// isDialError reports whether err occurred while connecting, that is, before the request was sent.
+func isDialError(err error) bool {
+	var oe *net.OpError
+	return errors.As(err, &oe) && oe.Op == "dial"
+}
+
+func isIdempotent(req *http.Request) bool {
+	if v, ok := req.Context().Value(idempotentKey{}).(bool); ok && v {
+		return true
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryAt returns the time the server asks the client to wait until before retrying.
+// Retry-After, in seconds or as an HTTP date, takes precedence over X-Ratelimit-Reset,
+// which is used only for 429 responses. It returns the zero time if neither is given.
+func retryAt(resp *http.Response, now time.Time) time.Time {
+	if s := resp.Header.Get(retryAfterHeaderKey); s != "" {
+		if seconds, err := strconv.ParseInt(s, 10, 64); err == nil && seconds >= 0 {
+			return now.Add(time.Duration(seconds) * time.Second)
+		}
+		if t, err := http.ParseTime(s); err == nil {
+			return t
+		}
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if seconds, err := strconv.ParseInt(resp.Header.Get(resetHeaderKey), 10, 64); err == nil {
+			return time.Unix(seconds, 0)
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,219 @@
+package backlog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy_Retryable(t *testing.T) {
+	type args struct {
+		method     string
+		status     int
+		err        error
+		idempotent bool
+	}
+	tests := []struct {
+		name     string
+		policy   *RetryPolicy
+		args     args
+		expected bool
+	}{
+		{
+			name:     "default retryable status",
+			policy:   DefaultRetryPolicy(),
+			args:     args{method: http.MethodPatch, status: http.StatusBadGateway},
+			expected: true,
+		},
+		{
+			name:     "default non-retryable status",
+			policy:   DefaultRetryPolicy(),
+			args:     args{method: http.MethodGet, status: http.StatusBadRequest},
+			expected: false,
+		},
+		{
+			name:     "default network error",
+			policy:   DefaultRetryPolicy(),
+			args:     args{method: http.MethodGet, err: syscall.ECONNRESET},
+			expected: true,
+		},
+		{
+			name:     "network errors disabled",
+			policy:   &RetryPolicy{},
+			args:     args{method: http.MethodGet, err: syscall.ECONNRESET},
+			expected: false,
+		},
+		{
+			name:     "non-transient network error",
+			policy:   &RetryPolicy{NetworkErrors: true},
+			args:     args{method: http.MethodGet, err: errors.New("x509: certificate signed by unknown authority")},
+			expected: false,
+		},
+		{
+			name:     "network error of idempotent method",
+			policy:   &RetryPolicy{NetworkErrors: true},
+			args:     args{method: http.MethodDelete, err: io.ErrUnexpectedEOF},
+			expected: true,
+		},
+		{
+			name:     "network error of non-idempotent method",
+			policy:   &RetryPolicy{NetworkErrors: true},
+			args:     args{method: http.MethodPost, err: syscall.ECONNRESET},
+			expected: false,
+		},
+		{
+			name:     "network error of request marked idempotent",
+			policy:   &RetryPolicy{NetworkErrors: true},
+			args:     args{method: http.MethodPatch, err: syscall.ECONNRESET, idempotent: true},
+			expected: true,
+		},
+		{
+			name:     "dial error of non-idempotent method",
+			policy:   &RetryPolicy{NetworkErrors: true},
+			args:     args{method: http.MethodPost, err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}},
+			expected: true,
+		},
+		{
+			name:     "status of non-idempotent method",
+			policy:   &RetryPolicy{Statuses: []int{http.StatusServiceUnavailable}, IdempotentOnly: true},
+			args:     args{method: http.MethodPost, status: http.StatusServiceUnavailable},
+			expected: false,
+		},
+		{
+			name:     "too many requests of non-idempotent method",
+			policy:   &RetryPolicy{Statuses: []int{http.StatusTooManyRequests}, IdempotentOnly: true},
+			args:     args{method: http.MethodPost, status: http.StatusTooManyRequests},
+			expected: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.args.idempotent {
+				ctx = WithIdempotent(ctx)
+			}
+			req, _ := http.NewRequestWithContext(ctx, tt.args.method, "https://example.com", nil)
+			var resp *http.Response
+			if tt.args.err == nil {
+				resp = &http.Response{StatusCode: tt.args.status, Header: http.Header{}}
+			}
+			assert.Equal(t, tt.expected, tt.policy.Retryable(req, resp, tt.args.err))
+		})
+	}
+}
+
+func TestRetryPolicy_Retryable_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://example.com", nil)
+	assert.False(t, (&RetryPolicy{NetworkErrors: true}).Retryable(req, nil, context.Canceled))
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestIsTransientNetworkError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{
+			name:     "timeout",
+			err:      &url.Error{Op: "Get", URL: "https://example.com", Err: timeoutError{}},
+			expected: true,
+		},
+		{
+			name:     "connection reset",
+			err:      &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)},
+			expected: true,
+		},
+		{
+			name:     "eof",
+			err:      fmt.Errorf("wrapped: %w", io.EOF),
+			expected: true,
+		},
+		{
+			name:     "unknown host",
+			err:      &net.DNSError{Err: "no such host", Name: "example.invalid", IsNotFound: true},
+			expected: false,
+		},
+		{
+			name:     "other",
+			err:      errors.New("network error"),
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsTransientNetworkError(tt.err))
+		})
+	}
+}
+
+func Test_retryAt(t *testing.T) {
+	now := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	type args struct {
+		status int
+		header http.Header
+	}
+	tests := []struct {
+		name     string
+		args     args
+		expected time.Time
+	}{
+		{
+			name:     "retry-after seconds",
+			args:     args{status: http.StatusServiceUnavailable, header: http.Header{retryAfterHeaderKey: {"120"}}},
+			expected: now.Add(2 * time.Minute),
+		},
+		{
+			name:     "retry-after date",
+			args:     args{status: http.StatusServiceUnavailable, header: http.Header{retryAfterHeaderKey: {"Tue, 01 Apr 2025 00:05:00 GMT"}}},
+			expected: time.Date(2025, 4, 1, 0, 5, 0, 0, time.UTC),
+		},
+		{
+			name: "retry-after takes precedence over ratelimit-reset",
+			args: args{status: http.StatusTooManyRequests, header: http.Header{
+				retryAfterHeaderKey: {"60"},
+				resetHeaderKey:      {strconv.FormatInt(now.Add(time.Hour).Unix(), 10)},
+			}},
+			expected: now.Add(time.Minute),
+		},
+		{
+			name:     "ratelimit-reset",
+			args:     args{status: http.StatusTooManyRequests, header: http.Header{resetHeaderKey: {strconv.FormatInt(now.Add(time.Hour).Unix(), 10)}}},
+			expected: now.Add(time.Hour),
+		},
+		{
+			name:     "ratelimit-reset of non-429",
+			args:     args{status: http.StatusServiceUnavailable, header: http.Header{resetHeaderKey: {strconv.FormatInt(now.Add(time.Hour).Unix(), 10)}}},
+			expected: time.Time{},
+		},
+		{
+			name:     "invalid retry-after",
+			args:     args{status: http.StatusServiceUnavailable, header: http.Header{retryAfterHeaderKey: {"soon"}}},
+			expected: time.Time{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := retryAt(&http.Response{StatusCode: tt.args.status, Header: tt.args.header}, now)
+			assert.True(t, tt.expected.Equal(actual), "expected %v, actual %v", tt.expected, actual)
+		})
+	}
+}
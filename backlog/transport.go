@@ -1,37 +1,37 @@
 package backlog
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"math/rand/v2"
 	"net/http"
-	"strconv"
+	"sync"
 	"time"
 )
 
-const resetHeaderKey = "X-Ratelimit-Reset"
-
 var _ http.RoundTripper = (*RetryableTransport)(nil)
 
-var retryableStatus = map[int]struct{}{
-	http.StatusTooManyRequests:     {}, // 429
-	http.StatusInternalServerError: {}, // 500
-	http.StatusBadGateway:          {}, // 502
-	http.StatusServiceUnavailable:  {}, // 503
-	http.StatusGatewayTimeout:      {}, // 504
-}
-
-// RetryableTransport is a custom HTTP transport that retries requests on certain status codes.
+// RetryableTransport is a custom HTTP transport that retries requests as decided by Policy.
+// It is safe for concurrent use, and all requests sent through it share one rate limit budget:
+// once the limit is exhausted, every request waits until it is reset.
 type RetryableTransport struct {
 	Transport        http.RoundTripper `json:"-"`
 	InitialInterval  time.Duration     `json:"initialInterval"`
 	MaxInterval      time.Duration     `json:"maxInterval"`
 	MaxRetryAttempts int               `json:"maxRetryAttempts"`
 	MaxJitterMilli   int               `json:"maxJitterMilli"`
+	Policy           *RetryPolicy      `json:"policy"`
+
+	// Observer, if not nil, receives the events of the requests, such as retries.
+	Observer Observer `json:"-"`
+
+	mu          sync.Mutex
+	pausedUntil time.Time
 }
 
-// NewRetryableTransport creates a new Transport with the specified parameters.
+// NewRetryableTransport creates a new Transport with the specified parameters and the default retry policy.
 func NewRetryableTransport(initialInterval, maxInterval time.Duration, maxRetryAttempts, maxJitterMilli int) *RetryableTransport {
 	if initialInterval < 0 {
 		initialInterval = 1 * time.Second
@@ -51,80 +51,140 @@ func NewRetryableTransport(initialInterval, maxInterval time.Duration, maxRetryA
 		MaxInterval:      maxInterval,
 		MaxRetryAttempts: maxRetryAttempts,
 		MaxJitterMilli:   maxJitterMilli,
+		Policy:           DefaultRetryPolicy(),
 	}
 	return t
 }
 
-// RoundTrip sends an HTTP request and retries if the response status code is 429 (Too Many Requests).
-// It waits for the time specified in the X-RateLimit-Reset header before retrying.
-// The retry attempts are limited by MaxRetryAttempts and a random jitter is added to the wait time.
-// The jitter is a random duration between 0 and MaxJitterMilli milliseconds.
+// RoundTrip sends an HTTP request and retries it if Policy says so, or DefaultRetryPolicy if Policy is nil.
+// It waits for the time given by the Retry-After header, or the X-RateLimit-Reset header of a 429 response,
+// and otherwise backs off exponentially from InitialInterval up to MaxInterval.
+// The attempts are limited by MaxRetryAttempts, and the request is sent once if it is less than 1.
+// A random jitter between 0 and MaxJitterMilli milliseconds is added to the wait time, and none if it is 0.
 func (o *RetryableTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	observer := o.Observer
+	if observer == nil {
+		observer = nopObserver{}
+	}
+
+	start := time.Now()
+	observer.RequestStarted(req)
+	resp, n, err := o.roundTrip(req, observer)
+	r := &Result{Err: err, Attempts: n, Elapsed: time.Since(start)}
+	if resp != nil {
+		r.Status = resp.StatusCode
+	}
+	observer.RequestFinished(req, r)
+	return resp, err
+}
+
+// roundTrip sends the request until it succeeds or is not retried, and returns the number of attempts as well.
+func (o *RetryableTransport) roundTrip(req *http.Request, observer Observer) (*http.Response, int, error) {
 	if req.Body != nil && req.GetBody == nil {
-		return nil, errors.New("request body is not rewindable")
+		return nil, 0, errors.New("request body is not rewindable")
 	}
 
-	status := 0
+	policy := o.Policy
+	if policy == nil {
+		policy = DefaultRetryPolicy()
+	}
 
-	for i := range o.MaxRetryAttempts {
+	attempts := max(o.MaxRetryAttempts, 1)
+	for i := range attempts {
+		if err := o.wait(req.Context()); err != nil {
+			return nil, i, err
+		}
 		var err error
 		if req.Body != nil && req.GetBody != nil && i > 0 {
 			req.Body, err = req.GetBody()
 			if err != nil {
-				return nil, fmt.Errorf("failed to rewind request body: %w", err)
+				return nil, i, fmt.Errorf("failed to rewind request body: %w", err)
 			}
 		}
 		resp, err := o.Transport.RoundTrip(req)
-		if err != nil {
-			return nil, fmt.Errorf("failed to request: %w", err)
+		a := &Attempt{Request: req, N: i + 1, Err: err}
+		if resp != nil {
+			a.Status = resp.StatusCode
 		}
-		status = resp.StatusCode
 
-		if _, ok := retryableStatus[status]; !ok {
-			return resp, nil
+		if !policy.Retryable(req, resp, err) {
+			if err != nil {
+				return nil, a.N, fmt.Errorf("failed to request: %w", err)
+			}
+			if rl := ParseRateLimit(resp.Header); rl != nil && rl.Remaining <= 0 {
+				o.pause(rl.Reset)
+			}
+			return resp, a.N, nil
 		}
 
-		if _, err := io.Copy(io.Discard, resp.Body); err != nil {
-			err2 := fmt.Errorf("failed to read response body: %w", err)
-			if err3 := resp.Body.Close(); err3 != nil {
-				err4 := errors.Join(err2, fmt.Errorf("failed to close response body: %w", err3))
-				return nil, err4
+		interval := min(o.InitialInterval<<i, o.MaxInterval)
+		if resp != nil {
+			if err := drain(resp); err != nil {
+				return nil, a.N, err
+			}
+			if resp.StatusCode == http.StatusTooManyRequests {
+				interval = o.InitialInterval
+			}
+			if at := retryAt(resp, time.Now()); time.Until(at) > 0 {
+				interval = time.Until(at)
+				o.pause(at)
 			}
-			return nil, err2
-		}
-		if err := resp.Body.Close(); err != nil {
-			err2 := fmt.Errorf("failed to close response body: %w", err)
-			return nil, err2
 		}
 
-		interval := o.InitialInterval
-		if resp.StatusCode == http.StatusTooManyRequests {
-			s := resp.Header.Get(resetHeaderKey)
-			if s != "" {
-				if seconds, err := strconv.ParseInt(s, 10, 64); err == nil {
-					now := time.Now().Unix()
-					if seconds > now {
-						interval = time.Duration(seconds-now) * time.Second
-					}
-				}
+		if a.N == attempts {
+			observer.GaveUp(a)
+			if err != nil {
+				return nil, a.N, fmt.Errorf("failed to request: %w", err)
 			}
-		} else {
-			interval = min(o.InitialInterval<<i, o.MaxInterval)
+			return nil, a.N, errors.New("max retry attempts exceeded")
+		}
+
+		a.Wait = interval
+		if o.MaxJitterMilli > 0 {
+			a.Wait += time.Duration(rand.N(o.MaxJitterMilli)) * time.Millisecond // #nosec G404
+		}
+		observer.RetryScheduled(a)
+		if err := sleep(req.Context(), a.Wait); err != nil {
+			return nil, a.N, err
 		}
+	}
 
-		jitter := time.Duration(rand.N(o.MaxJitterMilli)) * time.Millisecond // #nosec G404
-		interval += jitter
-		timer := time.NewTimer(interval)
+	return nil, 0, errors.New("max retry attempts exceeded")
+}
 
-		select {
-		case <-timer.C:
-		case <-req.Context().Done():
-			timer.Stop()
-			return nil, req.Context().Err()
+// drain reads the body of a response to be retried to the end and closes it so that the connection can be reused.
+func drain(resp *http.Response) error {
+	if _, err := io.Copy(io.Discard, resp.Body); err != nil {
+		err2 := fmt.Errorf("failed to read response body: %w", err)
+		if err3 := resp.Body.Close(); err3 != nil {
+			err4 := errors.Join(err2, fmt.Errorf("failed to close response body: %w", err3))
+			return err4
 		}
+		return err2
+	}
+	if err := resp.Body.Close(); err != nil {
+		err2 := fmt.Errorf("failed to close response body: %w", err)
+		return err2
+	}
+	return nil
+}
 
-		timer.Stop()
+// wait blocks until the shared rate limit is reset or the context is done.
+func (o *RetryableTransport) wait(ctx context.Context) error {
+	o.mu.Lock()
+	d := time.Until(o.pausedUntil)
+	o.mu.Unlock()
+	if d <= 0 {
+		return nil
 	}
+	return sleep(ctx, d)
+}
 
-	return nil, errors.New("max retry attempts exceeded")
+// pause makes subsequent requests wait until t.
+func (o *RetryableTransport) pause(t time.Time) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if t.After(o.pausedUntil) {
+		o.pausedUntil = t
+	}
 }
@@ -4,8 +4,11 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"syscall"
 	"testing"
 	"time"
 
@@ -42,6 +45,7 @@ func TestNewRetryableTransport(t *testing.T) {
 					MaxInterval:      30 * time.Second,
 					MaxRetryAttempts: 5,
 					MaxJitterMilli:   3000,
+					Policy:           DefaultRetryPolicy(),
 				},
 			},
 		},
@@ -60,6 +64,7 @@ func TestNewRetryableTransport(t *testing.T) {
 					MaxInterval:      30 * time.Second,
 					MaxRetryAttempts: 5,
 					MaxJitterMilli:   3000,
+					Policy:           DefaultRetryPolicy(),
 				},
 			},
 		},
@@ -81,6 +86,7 @@ func TestRetryableTransport_RoundTrip(t *testing.T) {
 		MaxInterval      time.Duration
 		MaxRetryAttempts int
 		MaxJitterMilli   int
+		Policy           *RetryPolicy
 	}
 	type args struct {
 		req *http.Request
@@ -156,6 +162,67 @@ func TestRetryableTransport_RoundTrip(t *testing.T) {
 				status:  http.StatusOK,
 			},
 		},
+		{
+			name: "retry without jitter",
+			fields: fields{
+				Transport: &mockRoundTripper{
+					responses: []*http.Response{
+						{
+							StatusCode: http.StatusInternalServerError,
+							Body:       io.NopCloser(bytes.NewBufferString("err")),
+							Header:     http.Header{},
+						},
+						{
+							StatusCode: http.StatusOK,
+							Body:       io.NopCloser(bytes.NewBufferString("ok")),
+							Header:     http.Header{},
+						},
+					},
+				},
+				InitialInterval:  1 * time.Millisecond,
+				MaxInterval:      10 * time.Millisecond,
+				MaxRetryAttempts: 3,
+				MaxJitterMilli:   0,
+			},
+			args: args{
+				req: func() *http.Request {
+					req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+					return req
+				}(),
+			},
+			expected: expected{
+				isError: false,
+				status:  http.StatusOK,
+			},
+		},
+		{
+			name: "zero attempts send once",
+			fields: fields{
+				Transport: &mockRoundTripper{
+					responses: []*http.Response{
+						{
+							StatusCode: http.StatusOK,
+							Body:       io.NopCloser(bytes.NewBufferString("ok")),
+							Header:     http.Header{},
+						},
+					},
+				},
+				InitialInterval:  1 * time.Millisecond,
+				MaxInterval:      10 * time.Millisecond,
+				MaxRetryAttempts: 0,
+				MaxJitterMilli:   0,
+			},
+			args: args{
+				req: func() *http.Request {
+					req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+					return req
+				}(),
+			},
+			expected: expected{
+				isError: false,
+				status:  http.StatusOK,
+			},
+		},
 		{
 			name: "retry always 500 return",
 			fields: fields{
@@ -343,6 +410,122 @@ func TestRetryableTransport_RoundTrip(t *testing.T) {
 				status:  0,
 			},
 		},
+		{
+			name: "network error retried by policy",
+			fields: fields{
+				Transport: &mockRoundTripper{
+					errors: []error{io.ErrUnexpectedEOF},
+					responses: []*http.Response{
+						nil,
+						{
+							StatusCode: http.StatusOK,
+							Body:       io.NopCloser(bytes.NewBufferString("ok")),
+							Header:     http.Header{},
+						},
+					},
+				},
+				InitialInterval:  1 * time.Millisecond,
+				MaxInterval:      10 * time.Millisecond,
+				MaxRetryAttempts: 2,
+				MaxJitterMilli:   1,
+				Policy:           &RetryPolicy{NetworkErrors: true},
+			},
+			args: args{
+				req: func() *http.Request {
+					req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+					return req
+				}(),
+			},
+			expected: expected{
+				isError: false,
+				status:  http.StatusOK,
+			},
+		},
+		{
+			name: "network error not retried for non-idempotent method",
+			fields: fields{
+				Transport: &mockRoundTripper{
+					errors: []error{syscall.ECONNRESET},
+				},
+				InitialInterval:  1 * time.Millisecond,
+				MaxInterval:      10 * time.Millisecond,
+				MaxRetryAttempts: 2,
+				MaxJitterMilli:   1,
+				Policy:           &RetryPolicy{NetworkErrors: true},
+			},
+			args: args{
+				req: func() *http.Request {
+					req, _ := http.NewRequestWithContext(context.Background(), http.MethodPost, url, nil)
+					return req
+				}(),
+			},
+			expected: expected{
+				isError: true,
+				status:  0,
+			},
+		},
+		{
+			name: "status not retried by policy",
+			fields: fields{
+				Transport: &mockRoundTripper{
+					responses: []*http.Response{
+						{
+							StatusCode: http.StatusInternalServerError,
+							Body:       io.NopCloser(bytes.NewBufferString("err")),
+							Header:     http.Header{},
+						},
+					},
+				},
+				InitialInterval:  1 * time.Millisecond,
+				MaxInterval:      10 * time.Millisecond,
+				MaxRetryAttempts: 2,
+				MaxJitterMilli:   1,
+				Policy:           &RetryPolicy{Statuses: []int{http.StatusServiceUnavailable}},
+			},
+			args: args{
+				req: func() *http.Request {
+					req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+					return req
+				}(),
+			},
+			expected: expected{
+				isError: false,
+				status:  http.StatusInternalServerError,
+			},
+		},
+		{
+			name: "503 with retry-after header",
+			fields: fields{
+				Transport: &mockRoundTripper{
+					responses: []*http.Response{
+						{
+							StatusCode: http.StatusServiceUnavailable,
+							Body:       io.NopCloser(bytes.NewBufferString("err")),
+							Header:     http.Header{retryAfterHeaderKey: {"0"}},
+						},
+						{
+							StatusCode: http.StatusOK,
+							Body:       io.NopCloser(bytes.NewBufferString("ok")),
+							Header:     http.Header{},
+						},
+					},
+				},
+				InitialInterval:  1 * time.Millisecond,
+				MaxInterval:      10 * time.Millisecond,
+				MaxRetryAttempts: 2,
+				MaxJitterMilli:   1,
+			},
+			args: args{
+				req: func() *http.Request {
+					req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+					return req
+				}(),
+			},
+			expected: expected{
+				isError: false,
+				status:  http.StatusOK,
+			},
+		},
 		{
 			name: "context canceled",
 			fields: fields{
@@ -539,6 +722,7 @@ func TestRetryableTransport_RoundTrip(t *testing.T) {
 				MaxInterval:      tt.fields.MaxInterval,
 				MaxRetryAttempts: tt.fields.MaxRetryAttempts,
 				MaxJitterMilli:   tt.fields.MaxJitterMilli,
+				Policy:           tt.fields.Policy,
 			}
 			resp, err := o.RoundTrip(tt.args.req)
 			if tt.expected.isError {
@@ -556,3 +740,140 @@ func TestRetryableTransport_RoundTrip(t *testing.T) {
 		})
 	}
 }
+
+type recordingObserver struct {
+	events  []string
+	results []Result
+}
+
+func (o *recordingObserver) RequestStarted(req *http.Request) {
+	o.events = append(o.events, "started "+req.Method)
+}
+
+func (o *recordingObserver) RetryScheduled(a *Attempt) {
+	o.events = append(o.events, fmt.Sprintf("retry %d: %s", a.N, a.Reason()))
+}
+
+func (o *recordingObserver) GaveUp(a *Attempt) {
+	o.events = append(o.events, fmt.Sprintf("gave up %d: %s", a.N, a.Reason()))
+}
+
+func (o *recordingObserver) RequestFinished(_ *http.Request, r *Result) {
+	o.events = append(o.events, "finished")
+	r.Elapsed = 0
+	o.results = append(o.results, *r)
+}
+
+func TestRetryableTransport_Observer(t *testing.T) {
+	observer := &recordingObserver{}
+	o := &RetryableTransport{
+		Transport: &mockRoundTripper{
+			errors: []error{syscall.ECONNRESET},
+			responses: []*http.Response{
+				nil,
+				{
+					StatusCode: http.StatusBadGateway,
+					Body:       io.NopCloser(bytes.NewBufferString("err")),
+					Header:     http.Header{},
+				},
+				{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(bytes.NewBufferString("ok")),
+					Header:     http.Header{},
+				},
+			},
+		},
+		InitialInterval:  1 * time.Millisecond,
+		MaxInterval:      10 * time.Millisecond,
+		MaxRetryAttempts: 3,
+		MaxJitterMilli:   1,
+		Observer:         observer,
+	}
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "https://example.com", nil)
+	resp, err := o.RoundTrip(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{
+		"started GET",
+		"retry 1: " + syscall.ECONNRESET.Error(),
+		"retry 2: 502 Bad Gateway",
+		"finished",
+	}, observer.events)
+	assert.Equal(t, []Result{{Status: http.StatusOK, Attempts: 3}}, observer.results)
+
+	// The last attempt is reported as a give-up, and the network error is returned.
+	observer = &recordingObserver{}
+	o.Observer = observer
+	o.Transport = &mockRoundTripper{errors: []error{io.EOF, io.EOF, io.EOF}}
+	_, err = o.RoundTrip(req)
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Equal(t, []string{"started GET", "retry 1: EOF", "retry 2: EOF", "gave up 3: EOF", "finished"}, observer.events)
+	assert.Equal(t, 3, observer.results[0].Attempts)
+	assert.ErrorIs(t, observer.results[0].Err, io.EOF)
+
+	// A request that is not retried is reported only when it starts and finishes.
+	observer = &recordingObserver{}
+	o.Observer = observer
+	o.Transport = &mockRoundTripper{
+		responses: []*http.Response{
+			{
+				StatusCode: http.StatusNotFound,
+				Body:       io.NopCloser(bytes.NewBufferString("not found")),
+				Header:     http.Header{},
+			},
+		},
+	}
+	_, err = o.RoundTrip(req)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"started GET", "finished"}, observer.events)
+	assert.Equal(t, []Result{{Status: http.StatusNotFound, Attempts: 1}}, observer.results)
+}
+
+func TestRetryableTransport_RateLimitBudget(t *testing.T) {
+	url := "https://example.com"
+	reset := time.Now().Add(1 * time.Hour).Unix()
+	o := &RetryableTransport{
+		Transport: &mockRoundTripper{
+			responses: []*http.Response{
+				{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(bytes.NewBufferString("ok")),
+					Header: http.Header{
+						limitHeaderKey:     {"150"},
+						remainingHeaderKey: {"0"},
+						resetHeaderKey:     {strconv.FormatInt(reset, 10)},
+					},
+				},
+			},
+		},
+		InitialInterval:  1 * time.Millisecond,
+		MaxInterval:      10 * time.Millisecond,
+		MaxRetryAttempts: 1,
+		MaxJitterMilli:   1,
+	}
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	resp, err := o.RoundTrip(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, time.Unix(reset, 0), o.pausedUntil)
+
+	// The exhausted budget makes the next request wait until the context is done.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req, _ = http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	_, err = o.RoundTrip(req)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestRetryableTransport_wait(t *testing.T) {
+	o := &RetryableTransport{}
+	o.pause(time.Now().Add(20 * time.Millisecond))
+	o.pause(time.Now())
+
+	start := time.Now()
+	assert.NoError(t, o.wait(context.Background()))
+	assert.GreaterOrEqual(t, time.Since(start), 15*time.Millisecond)
+	assert.NoError(t, o.wait(context.Background()))
+}
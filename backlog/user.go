@@ -0,0 +1,11 @@
+package backlog
+
+// User represents a Backlog user.
+type User struct {
+	ID          int64  `json:"id"`
+	UserID      string `json:"userId"`
+	Name        string `json:"name"`
+	RoleType    int    `json:"roleType"`
+	Lang        string `json:"lang,omitempty"`
+	MailAddress string `json:"mailAddress,omitempty"`
+}
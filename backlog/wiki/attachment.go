@@ -0,0 +1,190 @@
+package wiki
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/nekrassov01/backlog-utils/backlog"
+)
+
+// Attachments returns the files attached to a wiki page.
+func (c *Client) Attachments(id int64) ([]*backlog.Attachment, error) {
+	return c.AttachmentsContext(context.Background(), id)
+}
+
+// AttachmentsContext is like Attachments but uses the specified context for the request.
+func (c *Client) AttachmentsContext(ctx context.Context, id int64) ([]*backlog.Attachment, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("invalid wikiId: %d", id)
+	}
+
+	path := fmt.Sprintf("/api/v2/wikis/%d/attachments", id)
+	attachments, err := backlog.Call[[]*backlog.Attachment](ctx, c.Client, http.MethodGet, path, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wiki attachments: %w", err)
+	}
+
+	return attachments, nil
+}
+
+// DownloadAttachment copies the content of a file attached to a wiki page to w as it is received.
+// It returns the number of bytes written.
+func (c *Client) DownloadAttachment(id, attachmentID int64, w io.Writer) (int64, error) {
+	return c.DownloadAttachmentContext(context.Background(), id, attachmentID, w)
+}
+
+// DownloadAttachmentContext is like DownloadAttachment but uses the specified context for the request.
+func (c *Client) DownloadAttachmentContext(ctx context.Context, id, attachmentID int64, w io.Writer) (int64, error) {
+	if id <= 0 {
+		return 0, fmt.Errorf("invalid wikiId: %d", id)
+	}
+	if attachmentID <= 0 {
+		return 0, fmt.Errorf("invalid attachmentId: %d", attachmentID)
+	}
+
+	n, err := c.Download(ctx, fmt.Sprintf("/api/v2/wikis/%d/attachments/%d", id, attachmentID), w)
+	if err != nil {
+		return n, fmt.Errorf("failed to download wiki attachment: %w", err)
+	}
+
+	return n, nil
+}
+
+// SaveAttachment downloads a file attached to a wiki page into dir, named after the attachment,
+// and returns the path of the file. The content is written to a temporary file in dir first, which is
+// renamed to the path only when the download succeeds, so a failed download leaves no file behind and
+// keeps the file that was already there. An existing file is an error unless overwrite is set.
+// The wrap function, if not nil, wraps the file being written, such as to report progress.
+func (c *Client) SaveAttachment(id int64, a *backlog.Attachment, dir string, overwrite bool, wrap func(io.Writer) io.Writer) (string, error) {
+	return c.SaveAttachmentContext(context.Background(), id, a, dir, overwrite, wrap)
+}
+
+// SaveAttachmentContext is like SaveAttachment but uses the specified context for the request.
+func (c *Client) SaveAttachmentContext(ctx context.Context, id int64, a *backlog.Attachment, dir string, overwrite bool, wrap func(io.Writer) io.Writer) (string, error) {
+	if a == nil {
+		return "", errors.New("empty attachment")
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, AttachmentPath(a.Name))
+	if !overwrite {
+		if _, err := os.Lstat(path); err == nil {
+			return "", fmt.Errorf("failed to save wiki attachment: %s: %w", path, fs.ErrExist)
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+	}
+	f, err := os.CreateTemp(dir, ".bkl-attachment-*")
+	if err != nil {
+		return "", err
+	}
+	tmp := f.Name()
+	var w io.Writer = f
+	if wrap != nil {
+		w = wrap(f)
+	}
+	if _, err := c.DownloadAttachmentContext(ctx, id, a.ID, w); err != nil {
+		_ = f.Close()
+		_ = os.Remove(tmp)
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return "", err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return "", err
+	}
+
+	return path, nil
+}
+
+// AttachmentPath returns the file name for the attachment name, with path separators replaced with "_"
+// so that every attachment is saved directly in the download directory.
+func AttachmentPath(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r == 0 || r == '/' || r == '\\' {
+			return '_'
+		}
+		return r
+	}, name)
+	if name == "" || name == "." || name == ".." {
+		name = "_"
+	}
+	return name
+}
+
+// UploadAttachment uploads a file to the space and attaches it to a wiki page. The content is read from
+// the reader that open returns, which is called again if the upload is retried.
+// It sends nothing and returns nil if DryRun is set.
+func (c *Client) UploadAttachment(id int64, name string, open func() (io.ReadCloser, error)) ([]*backlog.Attachment, error) {
+	return c.UploadAttachmentContext(context.Background(), id, name, open)
+}
+
+// UploadAttachmentContext is like UploadAttachment but uses the specified context for the requests.
+func (c *Client) UploadAttachmentContext(ctx context.Context, id int64, name string, open func() (io.ReadCloser, error)) ([]*backlog.Attachment, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("invalid wikiId: %d", id)
+	}
+	if name == "" {
+		return nil, errors.New("empty attachment name")
+	}
+	if c.DryRun {
+		return nil, nil
+	}
+
+	a, err := c.Client.UploadAttachmentContext(ctx, name, open)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"attachmentId[]": {strconv.FormatInt(a.ID, 10)},
+	}
+	path := fmt.Sprintf("/api/v2/wikis/%d/attachments", id)
+	attachments, err := backlog.Call[[]*backlog.Attachment](ctx, c.Client, http.MethodPost, path, nil, form)
+	if err != nil {
+		return nil, fmt.Errorf("failed to attach file to wiki page: %w", err)
+	}
+
+	return attachments, nil
+}
+
+// DeleteAttachment deletes a file attached to a wiki page and returns it.
+// It sends nothing and returns nil if DryRun is set.
+func (c *Client) DeleteAttachment(id, attachmentID int64) (*backlog.Attachment, error) {
+	return c.DeleteAttachmentContext(context.Background(), id, attachmentID)
+}
+
+// DeleteAttachmentContext is like DeleteAttachment but uses the specified context for the request.
+func (c *Client) DeleteAttachmentContext(ctx context.Context, id, attachmentID int64) (*backlog.Attachment, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("invalid wikiId: %d", id)
+	}
+	if attachmentID <= 0 {
+		return nil, fmt.Errorf("invalid attachmentId: %d", attachmentID)
+	}
+	if c.DryRun {
+		return nil, nil
+	}
+
+	path := fmt.Sprintf("/api/v2/wikis/%d/attachments/%d", id, attachmentID)
+	a, err := backlog.Call[*backlog.Attachment](ctx, c.Client, http.MethodDelete, path, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete wiki attachment: %w", err)
+	}
+
+	return a, nil
+}
@@ -0,0 +1,515 @@
+package wiki
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/nekrassov01/backlog-utils/backlog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWiki_Attachments(t *testing.T) {
+	type expected struct {
+		value   []*backlog.Attachment
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		id       int64
+		status   int
+		body     string
+		expected expected
+	}{
+		{
+			name:   "basic",
+			id:     1,
+			status: 200,
+			body:   `[{"id":2,"name":"design.png","size":7,"createdUser":{"id":1,"userId":"user1","name":"User 1","roleType":1},"created":"2025-04-01T00:00:00Z"}]`,
+			expected: expected{
+				value: []*backlog.Attachment{
+					{
+						ID:          2,
+						Name:        "design.png",
+						Size:        7,
+						CreatedUser: &backlog.User{ID: 1, UserID: "user1", Name: "User 1", RoleType: 1},
+						Created:     time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+					},
+				},
+			},
+		},
+		{
+			name: "invalid id",
+			id:   0,
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name:   "api error",
+			id:     1,
+			status: 404,
+			body:   `{"errors":[{"message":"No wiki.","code":6,"moreInfo":""}]}`,
+			expected: expected{
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Client{
+				Client: &backlog.Client{
+					Writer:     io.Discard,
+					BaseURL:    "https://example.com",
+					APIKey:     "dummy",
+					HTTPClient: &http.Client{},
+				},
+			}
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				fmt.Sprintf("%s/api/v2/wikis/%d/attachments?apiKey=%s", o.BaseURL, tt.id, o.APIKey),
+				httpmock.NewStringResponder(tt.status, tt.body),
+			)
+			actual, err := o.Attachments(tt.id)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.value, actual)
+		})
+	}
+}
+
+func TestWiki_DownloadAttachment(t *testing.T) {
+	type args struct {
+		id           int64
+		attachmentID int64
+	}
+	type expected struct {
+		value   string
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		args     args
+		status   int
+		expected expected
+	}{
+		{
+			name:   "basic",
+			args:   args{id: 1, attachmentID: 2},
+			status: 200,
+			expected: expected{
+				value: "content",
+			},
+		},
+		{
+			name: "invalid id",
+			args: args{id: 0, attachmentID: 2},
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name: "invalid attachment id",
+			args: args{id: 1, attachmentID: 0},
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name:   "api error",
+			args:   args{id: 1, attachmentID: 2},
+			status: 404,
+			expected: expected{
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Client{
+				Client: &backlog.Client{
+					Writer:     io.Discard,
+					BaseURL:    "https://example.com",
+					APIKey:     "dummy",
+					HTTPClient: &http.Client{},
+				},
+			}
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			body := "content"
+			if tt.status != 200 {
+				body = `{"errors":[{"message":"No attachment.","code":6,"moreInfo":""}]}`
+			}
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				fmt.Sprintf("%s/api/v2/wikis/%d/attachments/%d?apiKey=%s", o.BaseURL, tt.args.id, tt.args.attachmentID, o.APIKey),
+				httpmock.NewStringResponder(tt.status, body),
+			)
+			buf := &bytes.Buffer{}
+			n, err := o.DownloadAttachment(tt.args.id, tt.args.attachmentID, buf)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, int64(len(tt.expected.value)), n)
+			assert.Equal(t, tt.expected.value, buf.String())
+		})
+	}
+}
+
+func TestAttachmentPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "basic",
+			input:    "design.png",
+			expected: "design.png",
+		},
+		{
+			name:     "separators",
+			input:    `../a/b\c.png`,
+			expected: ".._a_b_c.png",
+		},
+		{
+			name:     "parent directory",
+			input:    "..",
+			expected: "_",
+		},
+		{
+			name:     "empty",
+			input:    "",
+			expected: "_",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, AttachmentPath(tt.input))
+		})
+	}
+}
+
+func TestWiki_SaveAttachment(t *testing.T) {
+	type expected struct {
+		path    string
+		content string
+		isError bool
+	}
+	tests := []struct {
+		name       string
+		attachment *backlog.Attachment
+		status     int
+		existing   string
+		overwrite  bool
+		expected   expected
+	}{
+		{
+			name:       "basic",
+			attachment: &backlog.Attachment{ID: 2, Name: "design.png"},
+			status:     200,
+			expected: expected{
+				path:    "design.png",
+				content: "content",
+			},
+		},
+		{
+			name:       "existing file",
+			attachment: &backlog.Attachment{ID: 2, Name: "design.png"},
+			status:     200,
+			existing:   "old",
+			expected: expected{
+				content: "old",
+				isError: true,
+			},
+		},
+		{
+			name:       "overwrite",
+			attachment: &backlog.Attachment{ID: 2, Name: "design.png"},
+			status:     200,
+			existing:   "old",
+			overwrite:  true,
+			expected: expected{
+				path:    "design.png",
+				content: "content",
+			},
+		},
+		{
+			name:       "api error",
+			attachment: &backlog.Attachment{ID: 2, Name: "design.png"},
+			status:     404,
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name:       "api error over existing file",
+			attachment: &backlog.Attachment{ID: 2, Name: "design.png"},
+			status:     500,
+			existing:   "old",
+			overwrite:  true,
+			expected: expected{
+				content: "old",
+				isError: true,
+			},
+		},
+		{
+			name: "nil attachment",
+			expected: expected{
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Client{
+				Client: &backlog.Client{
+					Writer:     io.Discard,
+					BaseURL:    "https://example.com",
+					APIKey:     "dummy",
+					HTTPClient: &http.Client{},
+				},
+			}
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				fmt.Sprintf("%s/api/v2/wikis/1/attachments/2?apiKey=%s", o.BaseURL, o.APIKey),
+				httpmock.NewStringResponder(tt.status, "content"),
+			)
+			dir := filepath.Join(t.TempDir(), "attachments")
+			if tt.existing != "" {
+				assert.NoError(t, os.MkdirAll(dir, 0o750))
+				assert.NoError(t, os.WriteFile(filepath.Join(dir, tt.attachment.Name), []byte(tt.existing), 0o600))
+			}
+			var written int
+			wrap := func(w io.Writer) io.Writer {
+				return writerFunc(func(p []byte) (int, error) {
+					written += len(p)
+					return w.Write(p)
+				})
+			}
+			path, err := o.SaveAttachment(1, tt.attachment, dir, tt.overwrite, wrap)
+			if tt.attachment != nil {
+				// Nothing but the attachment is left in the directory, such as a temporary file.
+				entries, err := os.ReadDir(dir)
+				assert.NoError(t, err)
+				assert.LessOrEqual(t, len(entries), 1)
+			}
+			if tt.expected.isError {
+				assert.Error(t, err)
+				if tt.attachment == nil {
+					return
+				}
+				file := filepath.Join(dir, tt.attachment.Name)
+				if tt.expected.content == "" {
+					assert.NoFileExists(t, file)
+					return
+				}
+				b, err := os.ReadFile(file)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.content, string(b))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, filepath.Join(dir, tt.expected.path), path)
+			b, err := os.ReadFile(path)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.content, string(b))
+			assert.Equal(t, len(tt.expected.content), written)
+		})
+	}
+}
+
+type writerFunc func([]byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
+
+func TestWiki_UploadAttachment(t *testing.T) {
+	type fields struct {
+		DryRun bool
+	}
+	type expected struct {
+		value   []*backlog.Attachment
+		form    string
+		calls   int
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		id       int64
+		file     string
+		expected expected
+	}{
+		{
+			name: "basic",
+			id:   1,
+			file: "design.png",
+			expected: expected{
+				value: []*backlog.Attachment{{ID: 2, Name: "design.png", Size: 7}},
+				form:  "attachmentId%5B%5D=5",
+				calls: 2,
+			},
+		},
+		{
+			name:   "dry run",
+			fields: fields{DryRun: true},
+			id:     1,
+			file:   "design.png",
+			expected: expected{
+				calls: 0,
+			},
+		},
+		{
+			name: "invalid id",
+			id:   0,
+			file: "design.png",
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name: "empty name",
+			id:   1,
+			file: "",
+			expected: expected{
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Client{
+				Client: &backlog.Client{
+					Writer:     io.Discard,
+					BaseURL:    "https://example.com",
+					APIKey:     "dummy",
+					HTTPClient: &http.Client{},
+				},
+				DryRun: tt.fields.DryRun,
+			}
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			httpmock.RegisterResponder(
+				http.MethodPost,
+				o.BaseURL+"/api/v2/space/attachment?apiKey=dummy",
+				httpmock.NewStringResponder(200, `{"id":5,"name":"design.png","size":7}`),
+			)
+			var form string
+			httpmock.RegisterResponder(
+				http.MethodPost,
+				fmt.Sprintf("%s/api/v2/wikis/%d/attachments?apiKey=dummy", o.BaseURL, tt.id),
+				func(req *http.Request) (*http.Response, error) {
+					b, err := io.ReadAll(req.Body)
+					if err != nil {
+						return nil, err
+					}
+					form = string(b)
+					return httpmock.NewStringResponse(200, `[{"id":2,"name":"design.png","size":7}]`), nil
+				},
+			)
+			open := func() (io.ReadCloser, error) {
+				return io.NopCloser(strings.NewReader("content")), nil
+			}
+			actual, err := o.UploadAttachment(tt.id, tt.file, open)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.value, actual)
+			assert.Equal(t, tt.expected.form, form)
+			assert.Equal(t, tt.expected.calls, httpmock.GetTotalCallCount())
+		})
+	}
+}
+
+func TestWiki_DeleteAttachment(t *testing.T) {
+	type fields struct {
+		DryRun bool
+	}
+	type args struct {
+		id           int64
+		attachmentID int64
+	}
+	type expected struct {
+		value   *backlog.Attachment
+		calls   int
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		expected expected
+	}{
+		{
+			name: "basic",
+			args: args{id: 1, attachmentID: 2},
+			expected: expected{
+				value: &backlog.Attachment{ID: 2, Name: "design.png", Size: 7},
+				calls: 1,
+			},
+		},
+		{
+			name:   "dry run",
+			fields: fields{DryRun: true},
+			args:   args{id: 1, attachmentID: 2},
+			expected: expected{
+				calls: 0,
+			},
+		},
+		{
+			name: "invalid attachment id",
+			args: args{id: 1, attachmentID: 0},
+			expected: expected{
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Client{
+				Client: &backlog.Client{
+					Writer:     io.Discard,
+					BaseURL:    "https://example.com",
+					APIKey:     "dummy",
+					HTTPClient: &http.Client{},
+				},
+				DryRun: tt.fields.DryRun,
+			}
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			httpmock.RegisterResponder(
+				http.MethodDelete,
+				fmt.Sprintf("%s/api/v2/wikis/%d/attachments/%d?apiKey=dummy", o.BaseURL, tt.args.id, tt.args.attachmentID),
+				httpmock.NewStringResponder(200, `{"id":2,"name":"design.png","size":7}`),
+			)
+			actual, err := o.DeleteAttachment(tt.args.id, tt.args.attachmentID)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.value, actual)
+			assert.Equal(t, tt.expected.calls, httpmock.GetTotalCallCount())
+		})
+	}
+}
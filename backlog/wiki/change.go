@@ -0,0 +1,99 @@
+package wiki
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nekrassov01/backlog-utils/diff"
+)
+
+// Change represents a modification of a wiki page that is computed before it is sent.
+// Nil fields are left unchanged.
+type Change struct {
+	Page    *Page
+	Name    *string
+	Content *string
+
+	// Matches is the number of pattern matches in regexp replacement.
+	Matches int
+
+	// MailNotify makes Backlog send a notification mail of the update.
+	MailNotify bool
+}
+
+// PlanRename returns the change that replaces before with after in the page name.
+func PlanRename(page *Page, before, after string) (*Change, error) {
+	if page == nil {
+		return nil, errors.New("empty wiki page")
+	}
+	if before == "" {
+		return nil, errors.New("old strings must not be empty")
+	}
+	name := strings.ReplaceAll(page.Name, before, after)
+	return &Change{Page: page, Name: &name}, nil
+}
+
+// PlanReplace returns the change that replaces pairs of old and new strings in the page content.
+func PlanReplace(page *Page, pairs ...string) (*Change, error) {
+	if page == nil {
+		return nil, errors.New("empty wiki page")
+	}
+	if len(pairs) == 0 || len(pairs)%2 != 0 {
+		return nil, fmt.Errorf("number of old/new strings to replace does not match: %d", len(pairs))
+	}
+	content := strings.NewReplacer(pairs...).Replace(page.Content)
+	return &Change{Page: page, Content: &content}, nil
+}
+
+// PlanReplaceRegexp returns the change that replaces regular expressions in the page content.
+func PlanReplaceRegexp(page *Page, r *RegexpReplacer) (*Change, error) {
+	if page == nil {
+		return nil, errors.New("empty wiki page")
+	}
+	if r == nil {
+		return nil, errors.New("empty replacer")
+	}
+	content, n := r.Replace(page.Content)
+	return &Change{Page: page, Content: &content, Matches: n}, nil
+}
+
+// Changed reports whether the change modifies the page.
+func (ch *Change) Changed() bool {
+	return (ch.Name != nil && *ch.Name != ch.Page.Name) || (ch.Content != nil && *ch.Content != ch.Page.Content)
+}
+
+// Report returns the line reported for the change.
+// In dry-run mode it describes the change that would be made,
+// with a unified diff of the content before and after the change.
+func (ch *Change) Report(dryRun bool) string {
+	if !dryRun {
+		if ch.Name != nil {
+			return fmt.Sprintf("updated: %s => %s\n", ch.Page.Name, *ch.Name)
+		}
+		return fmt.Sprintf("updated: %d: %s%s\n", ch.Page.ID, ch.Page.Name, ch.matches())
+	}
+	if !ch.Changed() {
+		return fmt.Sprintf("unchanged: %d: %s\n", ch.Page.ID, ch.Page.Name)
+	}
+	var sb strings.Builder
+	if ch.Name != nil && *ch.Name != ch.Page.Name {
+		fmt.Fprintf(&sb, "would update: %s => %s\n", ch.Page.Name, *ch.Name)
+	}
+	if ch.Content != nil && *ch.Content != ch.Page.Content {
+		fmt.Fprintf(&sb, "would update: %d: %s%s\n", ch.Page.ID, ch.Page.Name, ch.matches())
+		sb.WriteString(diff.Unified("a/"+ch.Page.Name, "b/"+ch.Page.Name, ch.Page.Content, *ch.Content))
+	}
+	return sb.String()
+}
+
+func (ch *Change) matches() string {
+	switch ch.Matches {
+	case 0:
+		return ""
+	case 1:
+		return " (1 match)"
+	default:
+		return fmt.Sprintf(" (%d matches)", ch.Matches)
+	}
+}
@@ -0,0 +1,335 @@
+package wiki
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanRename(t *testing.T) {
+	type args struct {
+		page   *Page
+		before string
+		after  string
+	}
+	type expected struct {
+		name    string
+		changed bool
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "basic",
+			args: args{
+				page:   &Page{ID: 1, Name: "Old/Page"},
+				before: "Old",
+				after:  "New",
+			},
+			expected: expected{
+				name:    "New/Page",
+				changed: true,
+				isError: false,
+			},
+		},
+		{
+			name: "no match",
+			args: args{
+				page:   &Page{ID: 1, Name: "Page"},
+				before: "Old",
+				after:  "New",
+			},
+			expected: expected{
+				name:    "Page",
+				changed: false,
+				isError: false,
+			},
+		},
+		{
+			name: "empty page",
+			args: args{
+				page:   nil,
+				before: "Old",
+				after:  "New",
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name: "empty old string",
+			args: args{
+				page:   &Page{ID: 1, Name: "Page"},
+				before: "",
+				after:  "New",
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := PlanRename(tt.args.page, tt.args.before, tt.args.after)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.name, *actual.Name)
+			assert.Nil(t, actual.Content)
+			assert.Equal(t, tt.expected.changed, actual.Changed())
+		})
+	}
+}
+
+func TestPlanReplace(t *testing.T) {
+	type args struct {
+		page  *Page
+		pairs []string
+	}
+	type expected struct {
+		content string
+		changed bool
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "basic",
+			args: args{
+				page:  &Page{ID: 1, Content: "Hello Old World"},
+				pairs: []string{"Old", "New", "World", "Earth"},
+			},
+			expected: expected{
+				content: "Hello New Earth",
+				changed: true,
+				isError: false,
+			},
+		},
+		{
+			name: "no match",
+			args: args{
+				page:  &Page{ID: 1, Content: "Hello"},
+				pairs: []string{"Old", "New"},
+			},
+			expected: expected{
+				content: "Hello",
+				changed: false,
+				isError: false,
+			},
+		},
+		{
+			name: "empty page",
+			args: args{
+				page:  nil,
+				pairs: []string{"Old", "New"},
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name: "invalid pairs",
+			args: args{
+				page:  &Page{ID: 1, Content: "Hello"},
+				pairs: []string{"Old"},
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := PlanReplace(tt.args.page, tt.args.pairs...)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.content, *actual.Content)
+			assert.Nil(t, actual.Name)
+			assert.Equal(t, tt.expected.changed, actual.Changed())
+		})
+	}
+}
+
+func TestPlanReplaceRegexp(t *testing.T) {
+	r, err := NewRegexpReplacer(RegexpOptions{}, `#ISSUE-(\d+)`, "#PROJ-$1")
+	assert.NoError(t, err)
+	type args struct {
+		page     *Page
+		replacer *RegexpReplacer
+	}
+	type expected struct {
+		content string
+		matches int
+		changed bool
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "basic",
+			args: args{
+				page:     &Page{ID: 1, Content: "#ISSUE-1 #ISSUE-2"},
+				replacer: r,
+			},
+			expected: expected{
+				content: "#PROJ-1 #PROJ-2",
+				matches: 2,
+				changed: true,
+				isError: false,
+			},
+		},
+		{
+			name: "no match",
+			args: args{
+				page:     &Page{ID: 1, Content: "Hello"},
+				replacer: r,
+			},
+			expected: expected{
+				content: "Hello",
+				matches: 0,
+				changed: false,
+				isError: false,
+			},
+		},
+		{
+			name: "empty page",
+			args: args{
+				page:     nil,
+				replacer: r,
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name: "empty replacer",
+			args: args{
+				page:     &Page{ID: 1, Content: "Hello"},
+				replacer: nil,
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := PlanReplaceRegexp(tt.args.page, tt.args.replacer)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.content, *actual.Content)
+			assert.Equal(t, tt.expected.matches, actual.Matches)
+			assert.Equal(t, tt.expected.changed, actual.Changed())
+		})
+	}
+}
+
+func TestChange_Report(t *testing.T) {
+	newName := "New Name"
+	newContent := "line1\nline2 changed\n"
+	type args struct {
+		change *Change
+		dryRun bool
+	}
+	type expected struct {
+		value string
+	}
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "rename",
+			args: args{
+				change: &Change{Page: &Page{ID: 1, Name: "Old Name"}, Name: &newName},
+				dryRun: false,
+			},
+			expected: expected{
+				value: "updated: Old Name => New Name\n",
+			},
+		},
+		{
+			name: "replace",
+			args: args{
+				change: &Change{Page: &Page{ID: 1, Name: "Page", Content: "line1\nline2\n"}, Content: &newContent},
+				dryRun: false,
+			},
+			expected: expected{
+				value: "updated: 1: Page\n",
+			},
+		},
+		{
+			name: "dry run rename",
+			args: args{
+				change: &Change{Page: &Page{ID: 1, Name: "Old Name"}, Name: &newName},
+				dryRun: true,
+			},
+			expected: expected{
+				value: "would update: Old Name => New Name\n",
+			},
+		},
+		{
+			name: "dry run replace",
+			args: args{
+				change: &Change{Page: &Page{ID: 1, Name: "Page", Content: "line1\nline2\n"}, Content: &newContent},
+				dryRun: true,
+			},
+			expected: expected{
+				value: "would update: 1: Page\n--- a/Page\n+++ b/Page\n@@ -1,2 +1,2 @@\n line1\n-line2\n+line2 changed\n",
+			},
+		},
+		{
+			name: "replace with matches",
+			args: args{
+				change: &Change{Page: &Page{ID: 1, Name: "Page", Content: "line1\nline2\n"}, Content: &newContent, Matches: 2},
+				dryRun: false,
+			},
+			expected: expected{
+				value: "updated: 1: Page (2 matches)\n",
+			},
+		},
+		{
+			name: "dry run replace with match",
+			args: args{
+				change: &Change{Page: &Page{ID: 1, Name: "Page", Content: "line1\nline2\n"}, Content: &newContent, Matches: 1},
+				dryRun: true,
+			},
+			expected: expected{
+				value: "would update: 1: Page (1 match)\n--- a/Page\n+++ b/Page\n@@ -1,2 +1,2 @@\n line1\n-line2\n+line2 changed\n",
+			},
+		},
+		{
+			name: "dry run unchanged",
+			args: args{
+				change: &Change{Page: &Page{ID: 1, Name: "New Name"}, Name: &newName},
+				dryRun: true,
+			},
+			expected: expected{
+				value: "unchanged: 1: New Name\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected.value, tt.args.change.Report(tt.args.dryRun))
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package wiki
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportPath returns the path of the Markdown file for the page name, relative to the export directory.
+// The name is split into directories at each "/". Segments that are empty or refer to the current or
+// parent directory are replaced with "_" so that every page stays inside the export directory.
+func ExportPath(name string) string {
+	segments := strings.Split(name, "/")
+	for i, s := range segments {
+		s = strings.Map(func(r rune) rune {
+			if r == 0 || r == '\\' {
+				return '_'
+			}
+			return r
+		}, s)
+		if s == "" || s == "." || s == ".." {
+			s = "_"
+		}
+		segments[i] = s
+	}
+	return filepath.Join(segments...) + ".md"
+}
+
+// WriteMarkdown writes the page content preceded by YAML front matter holding the ID, project ID, tags and updated time.
+func WriteMarkdown(w io.Writer, page *Page) error {
+	var b strings.Builder
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "id: %d\n", page.ID)
+	fmt.Fprintf(&b, "projectId: %d\n", page.ProjectID)
+	if len(page.Tags) == 0 {
+		b.WriteString("tags: []\n")
+	} else {
+		b.WriteString("tags:\n")
+		for _, tag := range page.Tags {
+			// A JSON string is a valid double-quoted YAML scalar, so that any tag name is written safely.
+			s, err := json.Marshal(tag.Name)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(&b, "  - %s\n", s)
+		}
+	}
+	if !page.Updated.IsZero() {
+		fmt.Fprintf(&b, "updated: %s\n", page.Updated.UTC().Format(time.RFC3339))
+	}
+	b.WriteString("---\n")
+	b.WriteString(page.Content)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// PageName returns the page name for the path of a Markdown file relative to the export directory.
+// It is the reverse of ExportPath, except that escaped segments are not restored,
+// so a page is compared with a file by ExportPath of its name rather than by the name itself.
+func PageName(path string) string {
+	return strings.TrimSuffix(filepath.ToSlash(filepath.Clean(path)), ".md")
+}
+
+// ReadMarkdown reads a page written by WriteMarkdown. The ID, project ID, tags and updated time are taken
+// from the front matter and other keys are ignored. Without front matter, the whole input is the content.
+// The name of the page is not set because it is given by the path of the file.
+func ReadMarkdown(r io.Reader) (*Page, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	page := &Page{}
+	first, rest, _ := strings.Cut(string(b), "\n")
+	if strings.TrimSuffix(first, "\r") != "---" {
+		page.Content = string(b)
+		return page, nil
+	}
+
+	inTags := false
+	for {
+		if rest == "" {
+			return nil, errors.New("unterminated front matter")
+		}
+		var line string
+		line, rest, _ = strings.Cut(rest, "\n")
+		line = strings.TrimSuffix(line, "\r")
+		if line == "---" {
+			break
+		}
+		if item, ok := strings.CutPrefix(strings.TrimSpace(line), "- "); ok && inTags {
+			name, err := yamlString(item)
+			if err != nil {
+				return nil, fmt.Errorf("invalid tag: %s", item)
+			}
+			page.Tags = append(page.Tags, &Tag{Name: name})
+			continue
+		}
+		inTags = false
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			return nil, fmt.Errorf("invalid front matter: %s", line)
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "id":
+			if page.ID, err = strconv.ParseInt(value, 10, 64); err != nil {
+				return nil, fmt.Errorf("invalid id: %s", value)
+			}
+		case "projectId":
+			if page.ProjectID, err = strconv.ParseInt(value, 10, 64); err != nil {
+				return nil, fmt.Errorf("invalid projectId: %s", value)
+			}
+		case "tags":
+			inTags = value == ""
+			if !inTags && value != "[]" {
+				return nil, fmt.Errorf("invalid tags: %s", value)
+			}
+		case "updated":
+			if page.Updated, err = time.Parse(time.RFC3339, value); err != nil {
+				return nil, fmt.Errorf("invalid updated: %s", value)
+			}
+		}
+	}
+	page.Content = rest
+
+	return page, nil
+}
+
+// yamlString returns the value of a plain or double-quoted YAML scalar.
+func yamlString(s string) (string, error) {
+	if !strings.HasPrefix(s, `"`) {
+		return s, nil
+	}
+	var v string
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return "", err
+	}
+	return v, nil
+}
+
+// Export fetches the page and writes it as a Markdown file under dir at ExportPath of its name.
+// It returns the path of the written file.
+func (c *Client) Export(page *Page, dir string) (string, error) {
+	return c.ExportContext(context.Background(), page, dir)
+}
+
+// ExportContext is like Export but uses the specified context for the request.
+func (c *Client) ExportContext(ctx context.Context, page *Page, dir string) (string, error) {
+	if page == nil {
+		return "", errors.New("empty wiki page")
+	}
+
+	detail, err := c.GetContext(ctx, page.ID)
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, ExportPath(page.Name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return "", err
+	}
+	f, err := os.OpenFile(filepath.Clean(path), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return "", err
+	}
+	if err := WriteMarkdown(f, detail); err != nil {
+		_ = f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
@@ -0,0 +1,285 @@
+package wiki
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/nekrassov01/backlog-utils/backlog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		page     string
+		expected string
+	}{
+		{
+			name:     "top level",
+			page:     "Home",
+			expected: "Home.md",
+		},
+		{
+			name:     "nested",
+			page:     "Design/API/v2",
+			expected: filepath.Join("Design", "API", "v2.md"),
+		},
+		{
+			name:     "empty segments",
+			page:     "/Design//API/",
+			expected: filepath.Join("_", "Design", "_", "API", "_.md"),
+		},
+		{
+			name:     "parent directory",
+			page:     "../../etc/passwd",
+			expected: filepath.Join("_", "_", "etc", "passwd.md"),
+		},
+		{
+			name:     "backslash",
+			page:     `a\b`,
+			expected: "a_b.md",
+		},
+		{
+			name:     "non-ascii",
+			page:     "設計/概要",
+			expected: filepath.Join("設計", "概要.md"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ExportPath(tt.page))
+		})
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		page     *Page
+		expected string
+	}{
+		{
+			name: "full",
+			page: &Page{
+				ID:        1,
+				ProjectID: 123,
+				Name:      "Design/API",
+				Content:   "# API\n",
+				Tags:      []*Tag{{ID: 1, Name: "design"}, {ID: 2, Name: `say "hi": yes`}},
+				Updated:   time.Date(2025, 4, 1, 9, 0, 0, 0, time.FixedZone("JST", 9*60*60)),
+			},
+			expected: "---\nid: 1\nprojectId: 123\ntags:\n  - \"design\"\n  - \"say \\\"hi\\\": yes\"\nupdated: 2025-04-01T00:00:00Z\n---\n# API\n",
+		},
+		{
+			name: "no tags",
+			page: &Page{
+				ID:        2,
+				ProjectID: 123,
+				Name:      "Home",
+			},
+			expected: "---\nid: 2\nprojectId: 123\ntags: []\n---\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			assert.NoError(t, WriteMarkdown(buf, tt.page))
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
+
+func TestPageName(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{
+			name:     "top level",
+			path:     "Home.md",
+			expected: "Home",
+		},
+		{
+			name:     "nested",
+			path:     filepath.Join("Design", "API", "v2.md"),
+			expected: "Design/API/v2",
+		},
+		{
+			name:     "round trip",
+			path:     ExportPath("設計/概要"),
+			expected: "設計/概要",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, PageName(tt.path))
+		})
+	}
+}
+
+func TestReadMarkdown(t *testing.T) {
+	type expected struct {
+		page    *Page
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		input    string
+		expected expected
+	}{
+		{
+			name:  "written by WriteMarkdown",
+			input: "---\nid: 1\nprojectId: 123\ntags:\n  - \"design\"\n  - \"say \\\"hi\\\": yes\"\nupdated: 2025-04-01T00:00:00Z\n---\n# API\n",
+			expected: expected{
+				page: &Page{
+					ID:        1,
+					ProjectID: 123,
+					Content:   "# API\n",
+					Tags:      []*Tag{{Name: "design"}, {Name: `say "hi": yes`}},
+					Updated:   time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			name:  "plain tags, unknown keys and crlf",
+			input: "---\r\nid: 2\r\ntitle: API\r\ntags:\r\n  - design\r\n---\r\n# API\r\n",
+			expected: expected{
+				page: &Page{ID: 2, Content: "# API\r\n", Tags: []*Tag{{Name: "design"}}},
+			},
+		},
+		{
+			name:  "no front matter",
+			input: "# API\n---\n",
+			expected: expected{
+				page: &Page{Content: "# API\n---\n"},
+			},
+		},
+		{
+			name:  "empty front matter",
+			input: "---\n---\n",
+			expected: expected{
+				page: &Page{},
+			},
+		},
+		{
+			name:  "unterminated",
+			input: "---\nid: 1\n",
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name:  "invalid id",
+			input: "---\nid: one\n---\n",
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name:  "invalid tags",
+			input: "---\ntags: design\n---\n",
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name:  "invalid line",
+			input: "---\nid\n---\n",
+			expected: expected{
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ReadMarkdown(bytes.NewBufferString(tt.input))
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.page, actual)
+		})
+	}
+}
+
+func TestWiki_Export(t *testing.T) {
+	type expected struct {
+		path    string
+		content string
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		page     *Page
+		status   int
+		body     string
+		expected expected
+	}{
+		{
+			name:   "basic",
+			page:   &Page{ID: 1, ProjectID: 123, Name: "Design/API"},
+			status: 200,
+			body:   `{"id":1,"projectId":123,"name":"Design/API","content":"# API","tags":[{"id":1,"name":"design"}],"updated":"2025-04-01T00:00:00Z"}`,
+			expected: expected{
+				path:    filepath.Join("Design", "API.md"),
+				content: "---\nid: 1\nprojectId: 123\ntags:\n  - \"design\"\nupdated: 2025-04-01T00:00:00Z\n---\n# API",
+			},
+		},
+		{
+			name:   "not found",
+			page:   &Page{ID: 1, ProjectID: 123, Name: "Design/API"},
+			status: 404,
+			body:   `{"errors":[{"message":"No wiki.","code":6,"moreInfo":""}]}`,
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name: "nil page",
+			expected: expected{
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Client{
+				Client: &backlog.Client{
+					Writer:     io.Discard,
+					BaseURL:    "https://example.com",
+					APIKey:     "dummy",
+					HTTPClient: &http.Client{},
+				},
+			}
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			if tt.page != nil {
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					fmt.Sprintf("%s/api/v2/wikis/%d?apiKey=%s", o.BaseURL, tt.page.ID, o.APIKey),
+					httpmock.NewStringResponder(tt.status, tt.body),
+				)
+			}
+			dir := t.TempDir()
+			path, err := o.Export(tt.page, dir)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, filepath.Join(dir, tt.expected.path), path)
+			b, err := os.ReadFile(path)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.content, string(b))
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package wiki
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/nekrassov01/backlog-utils/backlog"
+	"github.com/nekrassov01/backlog-utils/diff"
+)
+
+// Revision represents a version of a wiki page recorded in its history.
+// ID is the ID of the history record, which is not the version, and is 0 if the API leaves it out.
+type Revision struct {
+	ID          int64         `json:"id,omitempty"`
+	PageID      int64         `json:"pageId"`
+	Version     int64         `json:"version"`
+	Name        string        `json:"name"`
+	Content     string        `json:"content,omitempty"`
+	CreatedUser *backlog.User `json:"createdUser,omitempty"`
+	Created     time.Time     `json:"created,omitzero"`
+}
+
+// ErrConflict is returned by Restore when the page has been edited since the expected version.
+var ErrConflict = errors.New("wiki page has been edited")
+
+// HistoryOptions represents the options for listing the history of a wiki page.
+// MinID and MaxID are sent as minId and maxId, which bound the IDs of the history records rather than the versions.
+// Zero values are not sent to the API.
+type HistoryOptions struct {
+	MinID     int64
+	MaxID     int64
+	Count     int
+	Ascending bool
+}
+
+// History returns a page of the revisions of a wiki page, newest first unless Ascending is set.
+func (c *Client) History(id int64, opts *HistoryOptions) ([]*Revision, error) {
+	return c.HistoryContext(context.Background(), id, opts)
+}
+
+// HistoryContext is like History but uses the specified context for the request.
+func (c *Client) HistoryContext(ctx context.Context, id int64, opts *HistoryOptions) ([]*Revision, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("invalid wikiId: %d", id)
+	}
+
+	path := fmt.Sprintf("/api/v2/wikis/%d/history", id)
+	revisions, err := backlog.Call[[]*Revision](ctx, c.Client, http.MethodGet, path, opts.values(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wiki page history: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// HistoryAll returns an iterator over all revisions of a wiki page matching the specified options.
+// The pages are fetched lazily by the IDs of the history records, or by version if the API leaves them out,
+// using the count of the options as the page size.
+// The iteration stops after maxItems revisions if maxItems is positive.
+func (c *Client) HistoryAll(ctx context.Context, id int64, opts *HistoryOptions, maxItems int) iter.Seq2[*Revision, error] {
+	var base HistoryOptions
+	if opts != nil {
+		base = *opts
+	}
+	fetch := func(ctx context.Context, p backlog.PageParams) ([]*Revision, error) {
+		o := base
+		o.Count = p.Count
+		if p.MinID != 0 {
+			o.MinID = p.MinID
+		}
+		if p.MaxID != 0 {
+			o.MaxID = p.MaxID
+		}
+		return c.HistoryContext(ctx, id, &o)
+	}
+	cursor := func(r *Revision) int64 {
+		if r.ID != 0 {
+			return r.ID
+		}
+		return r.Version
+	}
+	return backlog.PaginateByID(ctx, fetch, cursor, &backlog.PaginateOptions{
+		PageSize:  base.Count,
+		MaxItems:  maxItems,
+		Ascending: base.Ascending,
+	})
+}
+
+// Revision returns the specified version of a wiki page.
+// The history is fetched from the newest revision, so an old version of a long history takes several requests.
+func (c *Client) Revision(id, version int64) (*Revision, error) {
+	return c.RevisionContext(context.Background(), id, version)
+}
+
+// RevisionContext is like Revision but uses the specified context for the request.
+func (c *Client) RevisionContext(ctx context.Context, id, version int64) (*Revision, error) {
+	if version <= 0 {
+		return nil, fmt.Errorf("invalid version: %d", version)
+	}
+
+	// minId and maxId bound the IDs of the history records, which are not the versions, so the history
+	// is walked from the newest revision until the version is found or older versions are reached.
+	for r, err := range c.HistoryAll(ctx, id, nil, 0) {
+		if err != nil {
+			return nil, err
+		}
+		if r.Version == version {
+			return r, nil
+		}
+		if r.Version < version {
+			break
+		}
+	}
+
+	return nil, fmt.Errorf("version %d of wiki page %d not found", version, id)
+}
+
+// RestoreOptions represents the options for restoring a wiki page.
+type RestoreOptions struct {
+	// ExpectedVersion, if positive, makes Restore fail with ErrConflict unless it is the latest version
+	// of the page, so that edits made after the history was inspected are not overwritten.
+	ExpectedVersion int64
+
+	// MailNotify makes Backlog send a notification mail of the update.
+	MailNotify bool
+}
+
+// Restore sets the content of a wiki page back to that of the specified version.
+// The name of the page is kept. It returns the change, which is not sent if the content
+// is already the same or DryRun is set.
+func (c *Client) Restore(id, version int64, opts *RestoreOptions) (*Change, error) {
+	return c.RestoreContext(context.Background(), id, version, opts)
+}
+
+// RestoreContext is like Restore but uses the specified context for the requests.
+func (c *Client) RestoreContext(ctx context.Context, id, version int64, opts *RestoreOptions) (*Change, error) {
+	if opts == nil {
+		opts = &RestoreOptions{}
+	}
+
+	if opts.ExpectedVersion > 0 {
+		latest, err := c.HistoryContext(ctx, id, &HistoryOptions{Count: 1})
+		if err != nil {
+			return nil, err
+		}
+		if len(latest) == 0 || latest[0].Version != opts.ExpectedVersion {
+			var v int64
+			if len(latest) > 0 {
+				v = latest[0].Version
+			}
+			return nil, fmt.Errorf("%w: latest version of wiki page %d is %d, not %d", ErrConflict, id, v, opts.ExpectedVersion)
+		}
+	}
+
+	r, err := c.RevisionContext(ctx, id, version)
+	if err != nil {
+		return nil, err
+	}
+	page, err := c.GetContext(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := &Change{Page: page, Content: &r.Content, MailNotify: opts.MailNotify}
+	if !ch.Changed() {
+		return ch, nil
+	}
+	if err := c.ApplyContext(ctx, ch); err != nil {
+		return nil, err
+	}
+
+	return ch, nil
+}
+
+// Label returns the name of the revision with its version, or with "current" for version 0.
+func (r *Revision) Label() string {
+	if r.Version == 0 {
+		return r.Name + "@current"
+	}
+	return fmt.Sprintf("%s@%d", r.Name, r.Version)
+}
+
+// DiffRevisions returns a unified diff of the content from one revision to another.
+// It returns an empty string if the content is the same.
+func DiffRevisions(from, to *Revision) string {
+	return diff.Unified("a/"+from.Label(), "b/"+to.Label(), from.Content, to.Content)
+}
+
+func (o *HistoryOptions) values() url.Values {
+	v := url.Values{}
+	if o == nil {
+		return v
+	}
+	if o.MinID > 0 {
+		v.Set("minId", strconv.FormatInt(o.MinID, 10))
+	}
+	if o.MaxID > 0 {
+		v.Set("maxId", strconv.FormatInt(o.MaxID, 10))
+	}
+	if o.Count > 0 {
+		v.Set("count", strconv.Itoa(o.Count))
+	}
+	if o.Ascending {
+		v.Set("order", "asc")
+	}
+	return v
+}
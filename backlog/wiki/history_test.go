@@ -0,0 +1,463 @@
+package wiki
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/nekrassov01/backlog-utils/backlog"
+	"github.com/stretchr/testify/assert"
+)
+
+func revisionsJSON(versions ...int64) string {
+	items := make([]string, len(versions))
+	for i, v := range versions {
+		items[i] = fmt.Sprintf(`{"pageId":1,"version":%d,"name":"Home","content":"v%d\n","createdUser":{"id":%d,"userId":"user%d","name":"User %d","roleType":1},"created":"2025-04-0%dT00:00:00Z"}`, v, v, v, v, v, v)
+	}
+	return "[" + strings.Join(items, ",") + "]"
+}
+
+// historyResponder serves the versions of a wiki page from 1, whose history records have the IDs of ids
+// in order, treating minId and maxId as exclusive bounds of the IDs.
+func historyResponder(ids ...int64) httpmock.Responder {
+	return func(req *http.Request) (*http.Response, error) {
+		q := req.URL.Query()
+		var minID, maxID, count int64 = 0, math.MaxInt64, 20
+		if s := q.Get("count"); s != "" {
+			_, _ = fmt.Sscan(s, &count)
+		}
+		if s := q.Get("minId"); s != "" {
+			_, _ = fmt.Sscan(s, &minID)
+		}
+		if s := q.Get("maxId"); s != "" {
+			_, _ = fmt.Sscan(s, &maxID)
+		}
+		var items []string
+		for i, id := range ids {
+			if id > minID && id < maxID {
+				v := i + 1
+				items = append(items, fmt.Sprintf(`{"id":%d,"pageId":1,"version":%d,"name":"Home","content":"v%d\n"}`, id, v, v))
+			}
+		}
+		if q.Get("order") != "asc" {
+			slices.Reverse(items)
+		}
+		items = items[:min(int(count), len(items))]
+		return httpmock.NewStringResponse(200, "["+strings.Join(items, ",")+"]"), nil
+	}
+}
+
+func TestWiki_History(t *testing.T) {
+	type args struct {
+		id   int64
+		opts *HistoryOptions
+	}
+	type expected struct {
+		query   string
+		value   []*Revision
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "basic",
+			args: args{
+				id: 1,
+			},
+			expected: expected{
+				query: "apiKey=dummy",
+				value: []*Revision{
+					{
+						PageID:      1,
+						Version:     2,
+						Name:        "Home",
+						Content:     "v2\n",
+						CreatedUser: &backlog.User{ID: 2, UserID: "user2", Name: "User 2", RoleType: 1},
+						Created:     time.Date(2025, 4, 2, 0, 0, 0, 0, time.UTC),
+					},
+				},
+			},
+		},
+		{
+			name: "options",
+			args: args{
+				id:   1,
+				opts: &HistoryOptions{MinID: 1, MaxID: 3, Count: 10, Ascending: true},
+			},
+			expected: expected{
+				query: "apiKey=dummy&count=10&maxId=3&minId=1&order=asc",
+				value: []*Revision{
+					{
+						PageID:      1,
+						Version:     2,
+						Name:        "Home",
+						Content:     "v2\n",
+						CreatedUser: &backlog.User{ID: 2, UserID: "user2", Name: "User 2", RoleType: 1},
+						Created:     time.Date(2025, 4, 2, 0, 0, 0, 0, time.UTC),
+					},
+				},
+			},
+		},
+		{
+			name: "invalid id",
+			args: args{
+				id: 0,
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Client{
+				Client: &backlog.Client{
+					Writer:     io.Discard,
+					BaseURL:    "https://example.com",
+					APIKey:     "dummy",
+					HTTPClient: &http.Client{},
+				},
+			}
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				fmt.Sprintf("%s/api/v2/wikis/%d/history?%s", o.BaseURL, tt.args.id, tt.expected.query),
+				httpmock.NewStringResponder(200, revisionsJSON(2)),
+			)
+			actual, err := o.History(tt.args.id, tt.args.opts)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.value, actual)
+		})
+	}
+}
+
+func TestWiki_HistoryAll(t *testing.T) {
+	type args struct {
+		opts     *HistoryOptions
+		maxItems int
+	}
+	tests := []struct {
+		name     string
+		args     args
+		ids      []int64
+		expected []int64
+	}{
+		{
+			name:     "descending",
+			args:     args{opts: &HistoryOptions{Count: 2}},
+			expected: []int64{5, 4, 3, 2, 1},
+		},
+		{
+			name:     "ascending",
+			args:     args{opts: &HistoryOptions{Count: 2, Ascending: true}},
+			expected: []int64{1, 2, 3, 4, 5},
+		},
+		{
+			name:     "max items",
+			args:     args{opts: &HistoryOptions{Count: 2}, maxItems: 3},
+			expected: []int64{5, 4, 3},
+		},
+		{
+			name:     "record ids descending",
+			args:     args{opts: &HistoryOptions{Count: 2}},
+			ids:      []int64{11, 12, 20, 35, 36},
+			expected: []int64{5, 4, 3, 2, 1},
+		},
+		{
+			name:     "record ids ascending",
+			args:     args{opts: &HistoryOptions{Count: 2, Ascending: true}},
+			ids:      []int64{11, 12, 20, 35, 36},
+			expected: []int64{1, 2, 3, 4, 5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Client{
+				Client: &backlog.Client{
+					Writer:     io.Discard,
+					BaseURL:    "https://example.com",
+					APIKey:     "dummy",
+					HTTPClient: &http.Client{},
+				},
+			}
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			// Without record IDs, the responder serves versions 1 to 5 with exclusive bounds of the versions.
+			if tt.ids != nil {
+				httpmock.RegisterResponder(http.MethodGet, "https://example.com/api/v2/wikis/1/history", historyResponder(tt.ids...))
+			} else {
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					"https://example.com/api/v2/wikis/1/history",
+					func(req *http.Request) (*http.Response, error) {
+						q := req.URL.Query()
+						var minID, maxID, count int64 = 0, 6, 0
+						_, _ = fmt.Sscan(q.Get("count"), &count)
+						if s := q.Get("minId"); s != "" {
+							_, _ = fmt.Sscan(s, &minID)
+						}
+						if s := q.Get("maxId"); s != "" {
+							_, _ = fmt.Sscan(s, &maxID)
+						}
+						var versions []int64
+						for v := minID + 1; v < maxID; v++ {
+							versions = append(versions, v)
+						}
+						if q.Get("order") != "asc" {
+							for i, j := 0, len(versions)-1; i < j; i, j = i+1, j-1 {
+								versions[i], versions[j] = versions[j], versions[i]
+							}
+						}
+						versions = versions[:min(int(count), len(versions))]
+						return httpmock.NewStringResponse(200, revisionsJSON(versions...)), nil
+					},
+				)
+			}
+			var actual []int64
+			for r, err := range o.HistoryAll(context.Background(), 1, tt.args.opts, tt.args.maxItems) {
+				assert.NoError(t, err)
+				actual = append(actual, r.Version)
+			}
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestWiki_Revision(t *testing.T) {
+	type expected struct {
+		version int64
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		version  int64
+		expected expected
+	}{
+		{
+			name:    "basic",
+			version: 2,
+			expected: expected{
+				version: 2,
+			},
+		},
+		{
+			name:    "oldest",
+			version: 1,
+			expected: expected{
+				version: 1,
+			},
+		},
+		{
+			name:    "latest",
+			version: 5,
+			expected: expected{
+				version: 5,
+			},
+		},
+		{
+			name:    "not found",
+			version: 6,
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name:    "invalid version",
+			version: 0,
+			expected: expected{
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Client{
+				Client: &backlog.Client{
+					Writer:     io.Discard,
+					BaseURL:    "https://example.com",
+					APIKey:     "dummy",
+					HTTPClient: &http.Client{},
+				},
+			}
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			// The IDs of the history records are not contiguous and differ from the versions.
+			httpmock.RegisterResponder(http.MethodGet, o.BaseURL+"/api/v2/wikis/1/history", historyResponder(11, 12, 20, 35, 36))
+			actual, err := o.Revision(1, tt.version)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.version, actual.Version)
+			assert.Equal(t, fmt.Sprintf("v%d\n", tt.version), actual.Content)
+		})
+	}
+}
+
+func TestDiffRevisions(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     *Revision
+		to       *Revision
+		expected string
+	}{
+		{
+			name:     "versions",
+			from:     &Revision{Version: 1, Name: "Home", Content: "a\nb\n"},
+			to:       &Revision{Version: 2, Name: "Home", Content: "a\nc\n"},
+			expected: "--- a/Home@1\n+++ b/Home@2\n@@ -1,2 +1,2 @@\n a\n-b\n+c\n",
+		},
+		{
+			name:     "current",
+			from:     &Revision{Version: 2, Name: "Home", Content: "a\n"},
+			to:       &Revision{Name: "Top", Content: "b\n"},
+			expected: "--- a/Home@2\n+++ b/Top@current\n@@ -1 +1 @@\n-a\n+b\n",
+		},
+		{
+			name:     "same",
+			from:     &Revision{Version: 1, Name: "Home", Content: "a\n"},
+			to:       &Revision{Version: 2, Name: "Home", Content: "a\n"},
+			expected: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, DiffRevisions(tt.from, tt.to))
+		})
+	}
+}
+
+func TestWiki_Restore(t *testing.T) {
+	type fields struct {
+		DryRun bool
+	}
+	type args struct {
+		version int64
+		opts    *RestoreOptions
+	}
+	type expected struct {
+		changed  bool
+		body     string
+		conflict bool
+		isError  bool
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		content  string
+		args     args
+		expected expected
+	}{
+		{
+			name:    "restore",
+			content: "v3\n",
+			args:    args{version: 2},
+			expected: expected{
+				changed: true,
+				body:    "content=v2%0A",
+			},
+		},
+		{
+			name:    "mail notify",
+			content: "v3\n",
+			args:    args{version: 2, opts: &RestoreOptions{MailNotify: true}},
+			expected: expected{
+				changed: true,
+				body:    "content=v2%0A&mailNotify=true",
+			},
+		},
+		{
+			name:    "expected version",
+			content: "v3\n",
+			args:    args{version: 2, opts: &RestoreOptions{ExpectedVersion: 3}},
+			expected: expected{
+				changed: true,
+				body:    "content=v2%0A",
+			},
+		},
+		{
+			name:    "conflict",
+			content: "v3\n",
+			args:    args{version: 2, opts: &RestoreOptions{ExpectedVersion: 2}},
+			expected: expected{
+				conflict: true,
+				isError:  true,
+			},
+		},
+		{
+			name:    "unchanged",
+			content: "v2\n",
+			args:    args{version: 2},
+		},
+		{
+			name:    "dry run",
+			fields:  fields{DryRun: true},
+			content: "v3\n",
+			args:    args{version: 2},
+			expected: expected{
+				changed: true,
+			},
+		},
+		{
+			name:    "version not found",
+			content: "v3\n",
+			args:    args{version: 4},
+			expected: expected{
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Client{
+				Client: &backlog.Client{
+					Writer:     io.Discard,
+					BaseURL:    "https://example.com",
+					APIKey:     "dummy",
+					HTTPClient: &http.Client{},
+				},
+				DryRun: tt.fields.DryRun,
+			}
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			httpmock.RegisterResponder(http.MethodGet, o.BaseURL+"/api/v2/wikis/1/history", historyResponder(11, 20, 35))
+			page := fmt.Sprintf(`{"id":1,"projectId":123,"name":"Home","content":%q}`, tt.content)
+			httpmock.RegisterResponder(http.MethodGet, o.BaseURL+"/api/v2/wikis/1?apiKey=dummy", httpmock.NewStringResponder(200, page))
+			var body string
+			httpmock.RegisterResponder(http.MethodPatch, o.BaseURL+"/api/v2/wikis/1?apiKey=dummy", func(req *http.Request) (*http.Response, error) {
+				b, err := io.ReadAll(req.Body)
+				if err != nil {
+					return nil, err
+				}
+				body = string(b)
+				return httpmock.NewStringResponse(200, page), nil
+			})
+			actual, err := o.Restore(1, tt.args.version, tt.args.opts)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected.conflict, errors.Is(err, ErrConflict))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.changed, actual.Changed())
+			assert.Equal(t, tt.expected.body, body)
+		})
+	}
+}
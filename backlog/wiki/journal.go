@@ -0,0 +1,99 @@
+package wiki
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Entry represents a journal record of a change to a wiki page.
+// Nil fields were not changed.
+type Entry struct {
+	PageID     int64     `json:"pageId"`
+	Time       time.Time `json:"time"`
+	OldName    *string   `json:"oldName,omitempty"`
+	NewName    *string   `json:"newName,omitempty"`
+	OldContent *string   `json:"oldContent,omitempty"`
+	NewContent *string   `json:"newContent,omitempty"`
+}
+
+// Journal writes entries as JSON lines so that changes can be rolled back later.
+// It is safe for concurrent use.
+type Journal struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	now func() time.Time
+}
+
+// NewJournal creates a new journal that writes to w.
+func NewJournal(w io.Writer) *Journal {
+	return &Journal{
+		enc: json.NewEncoder(w),
+		now: time.Now,
+	}
+}
+
+// Record writes the old and new values of the change.
+func (j *Journal) Record(ch *Change) error {
+	if ch == nil || ch.Page == nil {
+		return errors.New("empty wiki page")
+	}
+
+	e := &Entry{
+		PageID: ch.Page.ID,
+		Time:   j.now(),
+	}
+	if ch.Name != nil {
+		e.OldName = &ch.Page.Name
+		e.NewName = ch.Name
+	}
+	if ch.Content != nil {
+		e.OldContent = &ch.Page.Content
+		e.NewContent = ch.Content
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if err := j.enc.Encode(e); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	return nil
+}
+
+// ReadJournal reads all entries from r.
+func ReadJournal(r io.Reader) ([]*Entry, error) {
+	var entries []*Entry
+	dec := json.NewDecoder(r)
+	for {
+		e := &Entry{}
+		if err := dec.Decode(e); err != nil {
+			if errors.Is(err, io.EOF) {
+				return entries, nil
+			}
+			return nil, fmt.Errorf("failed to read journal: %w", err)
+		}
+		entries = append(entries, e)
+	}
+}
+
+// Revert returns the change that restores the old values of the entry.
+func (e *Entry) Revert() *Change {
+	page := &Page{ID: e.PageID}
+	ch := &Change{Page: page}
+	if e.OldName != nil {
+		if e.NewName != nil {
+			page.Name = *e.NewName
+		}
+		ch.Name = e.OldName
+	}
+	if e.OldContent != nil {
+		if e.NewContent != nil {
+			page.Content = *e.NewContent
+		}
+		ch.Content = e.OldContent
+	}
+	return ch
+}
@@ -0,0 +1,169 @@
+package wiki
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJournal_Record(t *testing.T) {
+	name := "New Name"
+	content := "New Content"
+	type args struct {
+		change *Change
+	}
+	type expected struct {
+		value   string
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "rename",
+			args: args{
+				change: &Change{Page: &Page{ID: 1, Name: "Old Name"}, Name: &name},
+			},
+			expected: expected{
+				value:   `{"pageId":1,"time":"2025-04-01T00:00:00Z","oldName":"Old Name","newName":"New Name"}` + "\n",
+				isError: false,
+			},
+		},
+		{
+			name: "replace",
+			args: args{
+				change: &Change{Page: &Page{ID: 1, Name: "Page", Content: "Old Content"}, Content: &content},
+			},
+			expected: expected{
+				value:   `{"pageId":1,"time":"2025-04-01T00:00:00Z","oldContent":"Old Content","newContent":"New Content"}` + "\n",
+				isError: false,
+			},
+		},
+		{
+			name: "empty change",
+			args: args{
+				change: nil,
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			j := NewJournal(buf)
+			j.now = func() time.Time { return time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC) }
+			err := j.Record(tt.args.change)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.value, buf.String())
+		})
+	}
+}
+
+func TestReadJournal(t *testing.T) {
+	type args struct {
+		s string
+	}
+	type expected struct {
+		value   []*Entry
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "basic",
+			args: args{
+				s: `{"pageId":1,"time":"2025-04-01T00:00:00Z","oldName":"Old","newName":"New"}` + "\n" +
+					`{"pageId":2,"time":"2025-04-01T00:00:01Z","oldContent":"a","newContent":"b"}` + "\n",
+			},
+			expected: expected{
+				value: []*Entry{
+					{PageID: 1, Time: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), OldName: new("Old"), NewName: new("New")},
+					{PageID: 2, Time: time.Date(2025, 4, 1, 0, 0, 1, 0, time.UTC), OldContent: new("a"), NewContent: new("b")},
+				},
+				isError: false,
+			},
+		},
+		{
+			name: "empty",
+			args: args{
+				s: "",
+			},
+			expected: expected{
+				value:   nil,
+				isError: false,
+			},
+		},
+		{
+			name: "invalid",
+			args: args{
+				s: `{"pageId":1,`,
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ReadJournal(strings.NewReader(tt.args.s))
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.value, actual)
+		})
+	}
+}
+
+func TestEntry_Revert(t *testing.T) {
+	type args struct {
+		entry *Entry
+	}
+	type expected struct {
+		value *Change
+	}
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "rename",
+			args: args{
+				entry: &Entry{PageID: 1, OldName: new("Old"), NewName: new("New")},
+			},
+			expected: expected{
+				value: &Change{Page: &Page{ID: 1, Name: "New"}, Name: new("Old")},
+			},
+		},
+		{
+			name: "replace",
+			args: args{
+				entry: &Entry{PageID: 1, OldContent: new("a"), NewContent: new("b")},
+			},
+			expected: expected{
+				value: &Change{Page: &Page{ID: 1, Content: "b"}, Content: new("a")},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected.value, tt.args.entry.Revert())
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package wiki
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PushAction represents what is done to push a local page to Backlog.
+type PushAction string
+
+const (
+	PushCreate PushAction = "create"
+	PushUpdate PushAction = "update"
+	PushDelete PushAction = "delete"
+)
+
+// PushOp represents an operation of a push. Local is nil for deletions and Remote is nil for creations.
+type PushOp struct {
+	Action PushAction
+	Local  *Page
+	Remote *Page
+}
+
+// PushResult represents the outcome of a push operation.
+type PushResult struct {
+	Changed bool   // whether the operation modifies Backlog
+	Report  string // line reported for the operation
+}
+
+// ReadDir reads the Markdown files under dir as pages named by PageName of their paths.
+// Files and directories whose names start with "." are skipped, such as .git.
+func ReadDir(dir string) ([]*Page, error) {
+	var pages []*Page
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || filepath.Ext(path) != ".md" {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(filepath.Clean(path))
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		page, err := ReadMarkdown(f)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		page.Name = PageName(rel)
+		pages = append(pages, page)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pages, nil
+}
+
+// PlanPush matches the local pages with the remote ones and returns the operations to push them.
+// A local page is matched by the ID in its front matter, or by its name if the ID is missing or not found,
+// and then by its path, so that a page whose name was escaped by ExportPath is still found.
+// Matched pages are updated and the others are created. If prune is set, remote pages that no local page
+// matches are deleted. Updates come first and deletions last, so that content is published before any is removed.
+func PlanPush(local, remote []*Page, prune bool) ([]*PushOp, error) {
+	byID := make(map[int64]*Page, len(remote))
+	byName := make(map[string]*Page, len(remote))
+	byPath := make(map[string]*Page, len(remote))
+	for _, page := range remote {
+		byID[page.ID] = page
+		byName[page.Name] = page
+		byPath[ExportPath(page.Name)] = page
+	}
+
+	var updates, creates, deletes []*PushOp
+	matched := make(map[int64]*Page, len(local))
+	for _, page := range local {
+		r, ok := byID[page.ID]
+		if page.ID == 0 || !ok {
+			r, ok = byName[page.Name]
+		}
+		if !ok {
+			r, ok = byPath[ExportPath(page.Name)]
+		}
+		if !ok {
+			creates = append(creates, &PushOp{Action: PushCreate, Local: page})
+			continue
+		}
+		if other, ok := matched[r.ID]; ok {
+			return nil, fmt.Errorf("local pages %s and %s are both pushed to wiki page %d", other.Name, page.Name, r.ID)
+		}
+		matched[r.ID] = page
+		updates = append(updates, &PushOp{Action: PushUpdate, Local: page, Remote: r})
+	}
+	if prune {
+		for _, page := range remote {
+			if _, ok := matched[page.ID]; !ok {
+				deletes = append(deletes, &PushOp{Action: PushDelete, Remote: page})
+			}
+		}
+	}
+
+	return append(append(updates, creates...), deletes...), nil
+}
+
+// Push sends the operation to Backlog. New pages are created in the project of projectID.
+// It sends nothing if DryRun is set and reports what would be done instead.
+func (c *Client) Push(projectID int64, op *PushOp) (*PushResult, error) {
+	return c.PushContext(context.Background(), projectID, op)
+}
+
+// PushContext is like Push but uses the specified context for the requests.
+func (c *Client) PushContext(ctx context.Context, projectID int64, op *PushOp) (*PushResult, error) {
+	if op == nil {
+		return nil, errors.New("empty push operation")
+	}
+
+	verb := func(s string) string {
+		if c.DryRun {
+			return "would " + s
+		}
+		return s + "d"
+	}
+
+	switch op.Action {
+	case PushCreate:
+		if _, err := c.CreateContext(ctx, &CreateInput{ProjectID: projectID, Name: op.Local.Name, Content: op.Local.Content}); err != nil {
+			return nil, err
+		}
+		return &PushResult{Changed: true, Report: fmt.Sprintf("%s: %s\n", verb("create"), op.Local.Name)}, nil
+
+	case PushUpdate:
+		// The list of pages has no content, so the page is fetched to compare the content.
+		page, err := c.GetContext(ctx, op.Remote.ID)
+		if err != nil {
+			return nil, err
+		}
+		ch := &Change{Page: page}
+		// The name taken from the path loses the segments escaped by ExportPath,
+		// so the remote name is kept as long as it is exported to the same path.
+		if ExportPath(op.Local.Name) != ExportPath(page.Name) {
+			ch.Name = &op.Local.Name
+		}
+		if op.Local.Content != page.Content {
+			ch.Content = &op.Local.Content
+		}
+		if !ch.Changed() {
+			return &PushResult{Report: fmt.Sprintf("unchanged: %d: %s\n", page.ID, page.Name)}, nil
+		}
+		if err := c.ApplyContext(ctx, ch); err != nil {
+			return nil, err
+		}
+		return &PushResult{Changed: true, Report: ch.Report(c.DryRun)}, nil
+
+	case PushDelete:
+		if _, err := c.DeleteContext(ctx, op.Remote.ID); err != nil {
+			return nil, err
+		}
+		return &PushResult{Changed: true, Report: fmt.Sprintf("%s: %d: %s\n", verb("delete"), op.Remote.ID, op.Remote.Name)}, nil
+	}
+
+	return nil, fmt.Errorf("unknown push action: %s", op.Action)
+}
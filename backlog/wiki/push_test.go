@@ -0,0 +1,321 @@
+package wiki
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/nekrassov01/backlog-utils/backlog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadDir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"Home.md":                            "---\nid: 1\nprojectId: 123\ntags: []\n---\nhome",
+		filepath.Join("Design", "API.md"):    "api",
+		filepath.Join("Design", "notes.txt"): "ignored",
+		filepath.Join(".git", "HEAD.md"):     "ignored",
+		".hidden.md":                         "ignored",
+	}
+	for path, content := range files {
+		path = filepath.Join(dir, path)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+
+	pages, err := ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []*Page{
+		{Name: "Design/API", Content: "api"},
+		{ID: 1, ProjectID: 123, Name: "Home", Content: "home"},
+	}, pages)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "Broken.md"), []byte("---\nid: one\n---\n"), 0o600))
+	_, err = ReadDir(dir)
+	assert.Error(t, err)
+
+	_, err = ReadDir(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
+
+func TestPlanPush(t *testing.T) {
+	remote := []*Page{
+		{ID: 1, Name: "Home"},
+		{ID: 2, Name: "Design/API"},
+		{ID: 3, Name: "Obsolete"},
+		{ID: 4, Name: "Design//API"},
+	}
+	type args struct {
+		local []*Page
+		prune bool
+	}
+	type expected struct {
+		ops     []*PushOp
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "match by id and name",
+			args: args{
+				local: []*Page{
+					{ID: 1, Name: "Top"},
+					{Name: "Design/API"},
+					{ID: 99, Name: "New"},
+				},
+			},
+			expected: expected{
+				ops: []*PushOp{
+					{Action: PushUpdate, Local: &Page{ID: 1, Name: "Top"}, Remote: remote[0]},
+					{Action: PushUpdate, Local: &Page{Name: "Design/API"}, Remote: remote[1]},
+					{Action: PushCreate, Local: &Page{ID: 99, Name: "New"}},
+				},
+			},
+		},
+		{
+			name: "prune",
+			args: args{
+				local: []*Page{
+					{Name: "New"},
+					{ID: 1, Name: "Home"},
+				},
+				prune: true,
+			},
+			expected: expected{
+				ops: []*PushOp{
+					{Action: PushUpdate, Local: &Page{ID: 1, Name: "Home"}, Remote: remote[0]},
+					{Action: PushCreate, Local: &Page{Name: "New"}},
+					{Action: PushDelete, Remote: remote[1]},
+					{Action: PushDelete, Remote: remote[2]},
+					{Action: PushDelete, Remote: remote[3]},
+				},
+			},
+		},
+		{
+			name: "match by path",
+			args: args{
+				local: []*Page{
+					{Name: "Design/_/API"},
+				},
+			},
+			expected: expected{
+				ops: []*PushOp{
+					{Action: PushUpdate, Local: &Page{Name: "Design/_/API"}, Remote: remote[3]},
+				},
+			},
+		},
+		{
+			name: "same remote page",
+			args: args{
+				local: []*Page{
+					{ID: 1, Name: "Top"},
+					{Name: "Home"},
+				},
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := PlanPush(tt.args.local, remote, tt.args.prune)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.ops, actual)
+		})
+	}
+}
+
+func TestWiki_Push(t *testing.T) {
+	type fields struct {
+		DryRun bool
+	}
+	type expected struct {
+		result  *PushResult
+		calls   map[string]int
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		op       *PushOp
+		expected expected
+	}{
+		{
+			name: "create",
+			op:   &PushOp{Action: PushCreate, Local: &Page{Name: "New", Content: "new"}},
+			expected: expected{
+				result: &PushResult{Changed: true, Report: "created: New\n"},
+				calls:  map[string]int{"POST https://example.com/api/v2/wikis": 1},
+			},
+		},
+		{
+			name:   "create dry run",
+			fields: fields{DryRun: true},
+			op:     &PushOp{Action: PushCreate, Local: &Page{Name: "New", Content: "new"}},
+			expected: expected{
+				result: &PushResult{Changed: true, Report: "would create: New\n"},
+				calls:  map[string]int{},
+			},
+		},
+		{
+			name: "update",
+			op:   &PushOp{Action: PushUpdate, Local: &Page{Name: "Home", Content: "new"}, Remote: &Page{ID: 1, Name: "Home"}},
+			expected: expected{
+				result: &PushResult{Changed: true, Report: "updated: 1: Home\n"},
+				calls: map[string]int{
+					"GET https://example.com/api/v2/wikis/1":   1,
+					"PATCH https://example.com/api/v2/wikis/1": 1,
+				},
+			},
+		},
+		{
+			name: "rename",
+			op:   &PushOp{Action: PushUpdate, Local: &Page{Name: "Top", Content: "old"}, Remote: &Page{ID: 1, Name: "Home"}},
+			expected: expected{
+				result: &PushResult{Changed: true, Report: "updated: Home => Top\n"},
+				calls: map[string]int{
+					"GET https://example.com/api/v2/wikis/1":   1,
+					"PATCH https://example.com/api/v2/wikis/1": 1,
+				},
+			},
+		},
+		{
+			name: "unchanged",
+			op:   &PushOp{Action: PushUpdate, Local: &Page{Name: "Home", Content: "old"}, Remote: &Page{ID: 1, Name: "Home"}},
+			expected: expected{
+				result: &PushResult{Report: "unchanged: 1: Home\n"},
+				calls:  map[string]int{"GET https://example.com/api/v2/wikis/1": 1},
+			},
+		},
+		{
+			name: "delete",
+			op:   &PushOp{Action: PushDelete, Remote: &Page{ID: 1, Name: "Home"}},
+			expected: expected{
+				result: &PushResult{Changed: true, Report: "deleted: 1: Home\n"},
+				calls:  map[string]int{"DELETE https://example.com/api/v2/wikis/1": 1},
+			},
+		},
+		{
+			name:   "delete dry run",
+			fields: fields{DryRun: true},
+			op:     &PushOp{Action: PushDelete, Remote: &Page{ID: 1, Name: "Home"}},
+			expected: expected{
+				result: &PushResult{Changed: true, Report: "would delete: 1: Home\n"},
+				calls:  map[string]int{},
+			},
+		},
+		{
+			name: "empty operation",
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name: "unknown action",
+			op:   &PushOp{Action: "move"},
+			expected: expected{
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Client{
+				Client: &backlog.Client{
+					Writer:     io.Discard,
+					BaseURL:    "https://example.com",
+					APIKey:     "dummy",
+					HTTPClient: &http.Client{},
+				},
+				DryRun: tt.fields.DryRun,
+			}
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			page := `{"id":1,"projectId":123,"name":"Home","content":"old"}`
+			httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s/api/v2/wikis?apiKey=%s", o.BaseURL, o.APIKey), httpmock.NewStringResponder(201, page))
+			for _, method := range []string{http.MethodGet, http.MethodPatch, http.MethodDelete} {
+				httpmock.RegisterResponder(method, fmt.Sprintf("%s/api/v2/wikis/1?apiKey=%s", o.BaseURL, o.APIKey), httpmock.NewStringResponder(200, page))
+			}
+			actual, err := o.Push(123, tt.op)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.result, actual)
+			calls := map[string]int{}
+			for k, v := range httpmock.GetCallCountInfo() {
+				if v > 0 {
+					calls[k[:len(k)-len("?apiKey=dummy")]] = v
+				}
+			}
+			assert.Equal(t, tt.expected.calls, calls)
+		})
+	}
+}
+
+func TestWiki_PushExported(t *testing.T) {
+	o := &Client{
+		Client: &backlog.Client{
+			Writer:     io.Discard,
+			BaseURL:    "https://example.com",
+			APIKey:     "dummy",
+			HTTPClient: &http.Client{},
+		},
+	}
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	// The names have segments that ExportPath escapes, so they cannot be restored from the paths.
+	remote := []*Page{
+		{ID: 1, ProjectID: 123, Name: "Home"},
+		{ID: 2, ProjectID: 123, Name: "Design//API"},
+		{ID: 3, ProjectID: 123, Name: `Design\Guide`},
+		{ID: 4, ProjectID: 123, Name: "../Notes"},
+	}
+	for _, page := range remote {
+		body, err := json.Marshal(&Page{ID: page.ID, ProjectID: page.ProjectID, Name: page.Name, Content: "content of " + page.Name})
+		assert.NoError(t, err)
+		httpmock.RegisterResponder(
+			http.MethodGet,
+			fmt.Sprintf("%s/api/v2/wikis/%d?apiKey=%s", o.BaseURL, page.ID, o.APIKey),
+			httpmock.NewBytesResponder(200, body),
+		)
+	}
+
+	dir := t.TempDir()
+	for _, page := range remote {
+		_, err := o.Export(page, dir)
+		assert.NoError(t, err)
+	}
+	local, err := ReadDir(dir)
+	assert.NoError(t, err)
+	ops, err := PlanPush(local, remote, true)
+	assert.NoError(t, err)
+	assert.Len(t, ops, len(remote))
+	for _, op := range ops {
+		assert.Equal(t, PushUpdate, op.Action)
+		res, err := o.Push(123, op)
+		assert.NoError(t, err)
+		assert.False(t, res.Changed, res.Report)
+	}
+	for k, v := range httpmock.GetCallCountInfo() {
+		if v > 0 {
+			assert.True(t, strings.HasPrefix(k, http.MethodGet+" "), k)
+		}
+	}
+}
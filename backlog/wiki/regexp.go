@@ -0,0 +1,71 @@
+package wiki
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// RegexpOptions represents the flags applied to every pattern of a RegexpReplacer.
+type RegexpOptions struct {
+	// Multiline makes ^ and $ match at line boundaries.
+	Multiline bool
+
+	// IgnoreCase makes the patterns match case-insensitively.
+	IgnoreCase bool
+}
+
+// RegexpReplacer replaces a list of regular expressions with templates.
+// Templates may refer to submatches as $1 or ${name}, as in regexp.Regexp.Expand.
+type RegexpReplacer struct {
+	rules []regexpRule
+}
+
+type regexpRule struct {
+	re   *regexp.Regexp
+	repl string
+}
+
+// NewRegexpReplacer returns a new RegexpReplacer from pairs of patterns and templates.
+// Replacements are performed in argument order.
+func NewRegexpReplacer(opts RegexpOptions, pairs ...string) (*RegexpReplacer, error) {
+	if len(pairs) == 0 || len(pairs)%2 != 0 {
+		return nil, fmt.Errorf("number of old/new strings to replace does not match: %d", len(pairs))
+	}
+
+	flags := ""
+	if opts.Multiline {
+		flags += "m"
+	}
+	if opts.IgnoreCase {
+		flags += "i"
+	}
+
+	rules := make([]regexpRule, 0, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		expr := pairs[i]
+		if expr == "" {
+			return nil, errors.New("old strings must not be empty")
+		}
+		if flags != "" {
+			expr = "(?" + flags + ")" + expr
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, regexpRule{re: re, repl: pairs[i+1]})
+	}
+
+	return &RegexpReplacer{rules: rules}, nil
+}
+
+// Replace returns a copy of s with all replacements performed and the number of matches.
+func (r *RegexpReplacer) Replace(s string) (string, int) {
+	n := 0
+	for _, rule := range r.rules {
+		n += len(rule.re.FindAllStringIndex(s, -1))
+		s = rule.re.ReplaceAllString(s, rule.repl)
+	}
+	return s, n
+}
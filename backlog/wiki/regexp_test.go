@@ -0,0 +1,152 @@
+package wiki
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegexpReplacer_Replace(t *testing.T) {
+	type args struct {
+		opts  RegexpOptions
+		pairs []string
+		s     string
+	}
+	type expected struct {
+		value   string
+		matches int
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "capture group",
+			args: args{
+				pairs: []string{`#ISSUE-(\d+)`, "#PROJ-$1"},
+				s:     "see #ISSUE-1 and #ISSUE-23",
+			},
+			expected: expected{
+				value:   "see #PROJ-1 and #PROJ-23",
+				matches: 2,
+				isError: false,
+			},
+		},
+		{
+			name: "named group",
+			args: args{
+				pairs: []string{`(?P<level>#+) (?P<title>.+)`, "${level} [${title}]"},
+				s:     "## Heading",
+			},
+			expected: expected{
+				value:   "## [Heading]",
+				matches: 1,
+				isError: false,
+			},
+		},
+		{
+			name: "multiple pairs in order",
+			args: args{
+				pairs: []string{"a", "b", "b", "c"},
+				s:     "ab",
+			},
+			expected: expected{
+				value:   "cc",
+				matches: 3,
+				isError: false,
+			},
+		},
+		{
+			name: "multiline",
+			args: args{
+				opts:  RegexpOptions{Multiline: true},
+				pairs: []string{`^\* `, "- "},
+				s:     "* one\n* two\n",
+			},
+			expected: expected{
+				value:   "- one\n- two\n",
+				matches: 2,
+				isError: false,
+			},
+		},
+		{
+			name: "without multiline",
+			args: args{
+				pairs: []string{`^\* `, "- "},
+				s:     "* one\n* two\n",
+			},
+			expected: expected{
+				value:   "- one\n* two\n",
+				matches: 1,
+				isError: false,
+			},
+		},
+		{
+			name: "ignore case",
+			args: args{
+				opts:  RegexpOptions{IgnoreCase: true},
+				pairs: []string{"todo", "TODO"},
+				s:     "Todo todo",
+			},
+			expected: expected{
+				value:   "TODO TODO",
+				matches: 2,
+				isError: false,
+			},
+		},
+		{
+			name: "no match",
+			args: args{
+				pairs: []string{"x", "y"},
+				s:     "abc",
+			},
+			expected: expected{
+				value:   "abc",
+				matches: 0,
+				isError: false,
+			},
+		},
+		{
+			name: "invalid pairs",
+			args: args{
+				pairs: []string{"x"},
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name: "empty pattern",
+			args: args{
+				pairs: []string{"", "y"},
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name: "invalid pattern",
+			args: args{
+				pairs: []string{"[", "y"},
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewRegexpReplacer(tt.args.opts, tt.args.pairs...)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			actual, n := r.Replace(tt.args.s)
+			assert.Equal(t, tt.expected.value, actual)
+			assert.Equal(t, tt.expected.matches, n)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package wiki
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// RunReport represents the outcome of a bulk operation on wiki pages.
+type RunReport struct {
+	Succeeded []*ReportEntry `json:"succeeded"`
+	Failed    []*ReportEntry `json:"failed"`
+
+	// Interrupted are the pages whose requests were in flight when the operation was canceled.
+	// They may or may not have been changed, so they should be checked before they are retried.
+	Interrupted []*ReportEntry `json:"interrupted"`
+
+	// Skipped are the pages that were not started, either after an earlier failure or after cancellation.
+	Skipped []*ReportEntry `json:"skipped"`
+}
+
+// ReportEntry represents a wiki page in a run report.
+// PageID is 0 for a page that does not exist yet, such as one to be created by a push.
+type ReportEntry struct {
+	PageID int64  `json:"pageId,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// NewRunReport creates a new empty run report.
+func NewRunReport() *RunReport {
+	return &RunReport{
+		Succeeded:   []*ReportEntry{},
+		Failed:      []*ReportEntry{},
+		Interrupted: []*ReportEntry{},
+		Skipped:     []*ReportEntry{},
+	}
+}
+
+// Succeed records that the page was processed.
+func (r *RunReport) Succeed(id int64, name string) {
+	r.Succeeded = append(r.Succeeded, &ReportEntry{PageID: id, Name: name})
+}
+
+// Fail records that the page failed with err.
+// The page ID of a PageError takes precedence over id.
+func (r *RunReport) Fail(id int64, name string, err error) {
+	r.Failed = append(r.Failed, newFailedEntry(id, name, err))
+}
+
+// Interrupt records that the page was in flight when the operation was canceled with err.
+// The page ID of a PageError takes precedence over id.
+func (r *RunReport) Interrupt(id int64, name string, err error) {
+	r.Interrupted = append(r.Interrupted, newFailedEntry(id, name, err))
+}
+
+// Skip records that the page was not processed.
+func (r *RunReport) Skip(id int64, name string) {
+	r.Skipped = append(r.Skipped, &ReportEntry{PageID: id, Name: name})
+}
+
+// Encode writes the report to w as indented JSON.
+func (r *RunReport) Encode(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+func newFailedEntry(id int64, name string, err error) *ReportEntry {
+	var pe *PageError
+	if errors.As(err, &pe) {
+		id, err = pe.PageID, pe.Err
+	}
+	return &ReportEntry{PageID: id, Name: name, Error: err.Error()}
+}
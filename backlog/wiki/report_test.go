@@ -0,0 +1,82 @@
+package wiki
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunReport_Encode(t *testing.T) {
+	type expected struct {
+		value string
+	}
+	tests := []struct {
+		name     string
+		report   func() *RunReport
+		expected expected
+	}{
+		{
+			name:   "empty",
+			report: NewRunReport,
+			expected: expected{
+				value: "{\n  \"succeeded\": [],\n  \"failed\": [],\n  \"interrupted\": [],\n  \"skipped\": []\n}\n",
+			},
+		},
+		{
+			name: "basic",
+			report: func() *RunReport {
+				r := NewRunReport()
+				r.Succeed(1, "a")
+				r.Fail(2, "b", errors.New("error"))
+				r.Fail(0, "c", &PageError{PageID: 3, Err: errors.New("page error")})
+				r.Interrupt(4, "d", errors.New("context canceled"))
+				r.Skip(0, "e")
+				return r
+			},
+			expected: expected{
+				value: `{
+  "succeeded": [
+    {
+      "pageId": 1,
+      "name": "a"
+    }
+  ],
+  "failed": [
+    {
+      "pageId": 2,
+      "name": "b",
+      "error": "error"
+    },
+    {
+      "pageId": 3,
+      "name": "c",
+      "error": "page error"
+    }
+  ],
+  "interrupted": [
+    {
+      "pageId": 4,
+      "name": "d",
+      "error": "context canceled"
+    }
+  ],
+  "skipped": [
+    {
+      "name": "e"
+    }
+  ]
+}
+`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			assert.NoError(t, tt.report().Encode(buf))
+			assert.Equal(t, tt.expected.value, buf.String())
+		})
+	}
+}
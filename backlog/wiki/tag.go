@@ -0,0 +1,107 @@
+package wiki
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/nekrassov01/backlog-utils/backlog"
+)
+
+// Tags returns the tags used by the wiki pages of the specified project key.
+func (c *Client) Tags(projectKey string) ([]*Tag, error) {
+	return c.TagsContext(context.Background(), projectKey)
+}
+
+// TagsContext is like Tags but uses the specified context for the request.
+func (c *Client) TagsContext(ctx context.Context, projectKey string) ([]*Tag, error) {
+	if projectKey == "" {
+		return nil, errors.New("empty project key")
+	}
+
+	query := url.Values{
+		"projectIdOrKey": {projectKey},
+	}
+	tags, err := backlog.Call[[]*Tag](ctx, c.Client, http.MethodGet, "/api/v2/wikis/tags", query, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wiki tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// HasTag reports whether the page has any of the specified tags.
+func (p *Page) HasTag(names ...string) bool {
+	for _, tag := range p.Tags {
+		if slices.Contains(names, tag.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// PlanTags returns the change that adds and removes tags of the page.
+// Backlog takes the tags of a wiki page from the "[tag]" prefixes of its name, so the change renames
+// the page to the tags followed by the name without them. Tags to remove take precedence over tags to add.
+func PlanTags(page *Page, add, remove []string) (*Change, error) {
+	if page == nil {
+		return nil, errors.New("empty wiki page")
+	}
+	if len(add) == 0 && len(remove) == 0 {
+		return nil, errors.New("no tags to add or remove")
+	}
+	for _, tag := range slices.Concat(add, remove) {
+		if tag == "" || strings.ContainsAny(tag, "[]") {
+			return nil, fmt.Errorf("invalid tag: %q", tag)
+		}
+	}
+
+	tags, base := splitTags(page.Name)
+	for _, tag := range page.Tags {
+		if !slices.Contains(tags, tag.Name) {
+			tags = append(tags, tag.Name)
+		}
+	}
+	for _, tag := range add {
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	tags = slices.DeleteFunc(tags, func(tag string) bool {
+		return slices.Contains(remove, tag)
+	})
+
+	name := base
+	if len(tags) > 0 {
+		name = "[" + strings.Join(tags, "][") + "] " + base
+	}
+	return &Change{Page: page, Name: &name}, nil
+}
+
+// splitTags splits the "[tag]" prefixes off the page name.
+func splitTags(name string) ([]string, string) {
+	var tags []string
+	rest := name
+	for strings.HasPrefix(rest, "[") {
+		end := strings.Index(rest, "]")
+		if end < 0 {
+			break
+		}
+		tag := rest[1:end]
+		if tag == "" || strings.Contains(tag, "[") {
+			break
+		}
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+		rest = rest[end+1:]
+	}
+	if len(tags) == 0 {
+		return nil, name
+	}
+	return tags, strings.TrimLeft(rest, " ")
+}
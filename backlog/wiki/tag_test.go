@@ -0,0 +1,235 @@
+package wiki
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/nekrassov01/backlog-utils/backlog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWiki_Tags(t *testing.T) {
+	type expected struct {
+		value   []*Tag
+		isError bool
+	}
+	tests := []struct {
+		name       string
+		projectKey string
+		status     int
+		body       string
+		expected   expected
+	}{
+		{
+			name:       "basic",
+			projectKey: "TEST",
+			status:     200,
+			body:       `[{"id":1,"name":"api"},{"id":2,"name":"draft"}]`,
+			expected: expected{
+				value: []*Tag{{ID: 1, Name: "api"}, {ID: 2, Name: "draft"}},
+			},
+		},
+		{
+			name:       "empty project key",
+			projectKey: "",
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name:       "api error",
+			projectKey: "TEST",
+			status:     404,
+			body:       `{"errors":[{"message":"No project.","code":6,"moreInfo":""}]}`,
+			expected: expected{
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Client{
+				Client: &backlog.Client{
+					Writer:     io.Discard,
+					BaseURL:    "https://example.com",
+					APIKey:     "dummy",
+					HTTPClient: &http.Client{},
+				},
+			}
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				fmt.Sprintf("%s/api/v2/wikis/tags?apiKey=%s&projectIdOrKey=%s", o.BaseURL, o.APIKey, tt.projectKey),
+				httpmock.NewStringResponder(tt.status, tt.body),
+			)
+			actual, err := o.Tags(tt.projectKey)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.value, actual)
+		})
+	}
+}
+
+func TestPage_HasTag(t *testing.T) {
+	page := &Page{Name: "Home", Tags: []*Tag{{ID: 1, Name: "api"}, {ID: 2, Name: "draft"}}}
+	assert.True(t, page.HasTag("draft"))
+	assert.True(t, page.HasTag("ui", "api"))
+	assert.False(t, page.HasTag("ui"))
+	assert.False(t, page.HasTag())
+	assert.False(t, (&Page{Name: "Home"}).HasTag("api"))
+}
+
+func TestPlanTags(t *testing.T) {
+	type args struct {
+		page   *Page
+		add    []string
+		remove []string
+	}
+	type expected struct {
+		name    string
+		changed bool
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "add to untagged page",
+			args: args{
+				page: &Page{ID: 1, Name: "Design/API"},
+				add:  []string{"api", "draft"},
+			},
+			expected: expected{
+				name:    "[api][draft] Design/API",
+				changed: true,
+			},
+		},
+		{
+			name: "add to tagged page",
+			args: args{
+				page: &Page{ID: 1, Name: "[api] Design/API", Tags: []*Tag{{ID: 1, Name: "api"}}},
+				add:  []string{"draft", "api"},
+			},
+			expected: expected{
+				name:    "[api][draft] Design/API",
+				changed: true,
+			},
+		},
+		{
+			name: "tags not in name",
+			args: args{
+				page: &Page{ID: 1, Name: "Design/API", Tags: []*Tag{{ID: 1, Name: "api"}}},
+				add:  []string{"draft"},
+			},
+			expected: expected{
+				name:    "[api][draft] Design/API",
+				changed: true,
+			},
+		},
+		{
+			name: "remove",
+			args: args{
+				page:   &Page{ID: 1, Name: "[api][draft]Design/API", Tags: []*Tag{{ID: 1, Name: "api"}, {ID: 2, Name: "draft"}}},
+				remove: []string{"draft"},
+			},
+			expected: expected{
+				name:    "[api] Design/API",
+				changed: true,
+			},
+		},
+		{
+			name: "remove last",
+			args: args{
+				page:   &Page{ID: 1, Name: "[draft] Design/API", Tags: []*Tag{{ID: 2, Name: "draft"}}},
+				remove: []string{"draft"},
+			},
+			expected: expected{
+				name:    "Design/API",
+				changed: true,
+			},
+		},
+		{
+			name: "remove wins over add",
+			args: args{
+				page:   &Page{ID: 1, Name: "Design/API"},
+				add:    []string{"draft"},
+				remove: []string{"draft"},
+			},
+			expected: expected{
+				name:    "Design/API",
+				changed: false,
+			},
+		},
+		{
+			name: "already tagged",
+			args: args{
+				page: &Page{ID: 1, Name: "[api] Design/API", Tags: []*Tag{{ID: 1, Name: "api"}}},
+				add:  []string{"api"},
+			},
+			expected: expected{
+				name:    "[api] Design/API",
+				changed: false,
+			},
+		},
+		{
+			name: "bracket in name",
+			args: args{
+				page: &Page{ID: 1, Name: "[] Notes [draft]"},
+				add:  []string{"api"},
+			},
+			expected: expected{
+				name:    "[api] [] Notes [draft]",
+				changed: true,
+			},
+		},
+		{
+			name: "nil page",
+			args: args{
+				add: []string{"api"},
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name: "no tags",
+			args: args{
+				page: &Page{ID: 1, Name: "Design/API"},
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name: "invalid tag",
+			args: args{
+				page: &Page{ID: 1, Name: "Design/API"},
+				add:  []string{"[api]"},
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := PlanTags(tt.args.page, tt.args.add, tt.args.remove)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.name, *actual.Name)
+			assert.Equal(t, tt.expected.changed, actual.Changed())
+		})
+	}
+}
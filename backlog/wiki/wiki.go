@@ -1,14 +1,15 @@
 package wiki
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
 	"regexp"
-	"strings"
+	"slices"
+	"strconv"
+	"time"
 
 	"github.com/nekrassov01/backlog-utils/backlog"
 )
@@ -16,14 +17,60 @@ import (
 // Client represents a Backlog wiki client.
 type Client struct {
 	*backlog.Client
+
+	// DryRun makes the client report changes without sending them.
+	DryRun bool
+
+	// Journal records the old values of pages that are changed, if set.
+	Journal *Journal
 }
 
 // Page represents a wiki page.
 type Page struct {
-	ID        int64  `json:"id"`
-	ProjectID int64  `json:"projectId"`
-	Name      string `json:"name"`
-	Content   string `json:"content,omitempty"`
+	ID        int64     `json:"id"`
+	ProjectID int64     `json:"projectId"`
+	Name      string    `json:"name"`
+	Content   string    `json:"content,omitempty"`
+	Tags      []*Tag    `json:"tags,omitempty"`
+	Updated   time.Time `json:"updated,omitzero"`
+}
+
+// Tag represents a tag of wiki pages.
+type Tag struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// CreateInput represents the parameters for creating a wiki page.
+type CreateInput struct {
+	ProjectID  int64
+	Name       string
+	Content    string
+	MailNotify bool
+}
+
+// UpdateInput represents the parameters for updating a wiki page.
+// Only non-nil fields are sent to the API.
+type UpdateInput struct {
+	Name       *string
+	Content    *string
+	MailNotify bool
+}
+
+// PageError represents an error that occurred while processing a wiki page.
+type PageError struct {
+	PageID int64
+	Err    error
+}
+
+// Error returns the error message with the page ID.
+func (e *PageError) Error() string {
+	return fmt.Sprintf("wiki page %d: %v", e.PageID, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *PageError) Unwrap() error {
+	return e.Err
 }
 
 // NewClient creates a new Backlog wiki client.
@@ -32,42 +79,27 @@ func NewClient(url, apiKey string, opts ...backlog.ClientOption) (*Client, error
 	if err != nil {
 		return nil, err
 	}
-	return &Client{o}, nil
+	return &Client{Client: o}, nil
 }
 
 // List returns a list of wiki pages for the specified project key.
-func (c *Client) List(projectKey, pattern string) ([]*Page, error) {
+// Pages are filtered by the pattern of their names and, if tags are specified, by having any of the tags.
+func (c *Client) List(projectKey, pattern string, tags ...string) ([]*Page, error) {
+	return c.ListContext(context.Background(), projectKey, pattern, tags...)
+}
+
+// ListContext is like List but uses the specified context for the request.
+func (c *Client) ListContext(ctx context.Context, projectKey, pattern string, tags ...string) ([]*Page, error) {
 	if projectKey == "" {
 		return nil, errors.New("empty project key")
 	}
 
-	uri := fmt.Sprintf("%s/api/v2/wikis?projectIdOrKey=%s&apiKey=%s", c.BaseURL, projectKey, c.APIKey)
-	req, err := http.NewRequest(http.MethodGet, uri, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-
-	//nolint:errcheck
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		msg := backlog.GetErrorMessage(resp)
-		return nil, fmt.Errorf("failed to list wikis: %d: %s", resp.StatusCode, msg)
+	query := url.Values{
+		"projectIdOrKey": {projectKey},
 	}
-
-	body, err := io.ReadAll(resp.Body)
+	pages, err := backlog.Call[[]*Page](ctx, c.Client, http.MethodGet, "/api/v2/wikis", query, nil)
 	if err != nil {
-		return nil, err
-	}
-
-	var pages []*Page
-	if err := json.Unmarshal(body, &pages); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to list wikis: %w", err)
 	}
 
 	if pattern != "" {
@@ -83,43 +115,110 @@ func (c *Client) List(projectKey, pattern string) ([]*Page, error) {
 		}
 		pages = matched
 	}
+	if len(tags) > 0 {
+		pages = slices.DeleteFunc(pages, func(page *Page) bool {
+			return !page.HasTag(tags...)
+		})
+	}
 
 	return pages, nil
 }
 
 // Get returns a wiki page.
 func (c *Client) Get(id int64) (*Page, error) {
+	return c.GetContext(context.Background(), id)
+}
+
+// GetContext is like Get but uses the specified context for the request.
+func (c *Client) GetContext(ctx context.Context, id int64) (*Page, error) {
 	if id <= 0 {
 		return nil, fmt.Errorf("invalid wikiId: %d", id)
 	}
 
-	uri := fmt.Sprintf("%s/api/v2/wikis/%d?apiKey=%s", c.BaseURL, id, c.APIKey)
-	req, err := http.NewRequest(http.MethodGet, uri, nil)
+	page, err := backlog.Call[*Page](ctx, c.Client, http.MethodGet, fmt.Sprintf("/api/v2/wikis/%d", id), nil, nil)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to get wiki page: %w", err)
+	}
+
+	return page, nil
+}
+
+// Create creates a wiki page. It sends nothing and returns nil if DryRun is set.
+func (c *Client) Create(in *CreateInput) (*Page, error) {
+	return c.CreateContext(context.Background(), in)
+}
+
+// CreateContext is like Create but uses the specified context for the request.
+func (c *Client) CreateContext(ctx context.Context, in *CreateInput) (*Page, error) {
+	if in == nil {
+		return nil, errors.New("empty wiki page input")
+	}
+	if in.ProjectID <= 0 {
+		return nil, fmt.Errorf("invalid projectId: %d", in.ProjectID)
+	}
+	if in.Name == "" {
+		return nil, errors.New("empty wiki page name")
+	}
+	if c.DryRun {
+		return nil, nil
 	}
 
-	resp, err := c.HTTPClient.Do(req)
+	page, err := backlog.Call[*Page](ctx, c.Client, http.MethodPost, "/api/v2/wikis", nil, in.values())
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to create wiki page: %w", err)
 	}
 
-	//nolint:errcheck
-	defer resp.Body.Close()
+	return page, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		msg := backlog.GetErrorMessage(resp)
-		return nil, fmt.Errorf("failed to get wiki page: %d: %s", resp.StatusCode, msg)
+// Update sets any combination of the name and content of a wiki page in one request.
+// It sends nothing and returns nil if DryRun is set.
+func (c *Client) Update(id int64, in *UpdateInput) (*Page, error) {
+	return c.UpdateContext(context.Background(), id, in)
+}
+
+// UpdateContext is like Update but uses the specified context for the request.
+func (c *Client) UpdateContext(ctx context.Context, id int64, in *UpdateInput) (*Page, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("invalid wikiId: %d", id)
+	}
+	if in == nil || in.Name == nil && in.Content == nil {
+		return nil, errors.New("no fields to update")
+	}
+	if c.DryRun {
+		return nil, nil
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	// Setting the fields to absolute values can be repeated safely, so the update is retried after network errors
+	// unless a repeated request could send the notification mail twice.
+	if !in.MailNotify {
+		ctx = backlog.WithIdempotent(ctx)
+	}
+	page, err := backlog.Call[*Page](ctx, c.Client, http.MethodPatch, fmt.Sprintf("/api/v2/wikis/%d", id), nil, in.values())
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to update wiki page: %w", err)
 	}
 
-	var page *Page
-	if err := json.Unmarshal(body, &page); err != nil {
-		return nil, err
+	return page, nil
+}
+
+// Delete deletes a wiki page. It sends nothing and returns nil if DryRun is set.
+func (c *Client) Delete(id int64) (*Page, error) {
+	return c.DeleteContext(context.Background(), id)
+}
+
+// DeleteContext is like Delete but uses the specified context for the request.
+func (c *Client) DeleteContext(ctx context.Context, id int64) (*Page, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("invalid wikiId: %d", id)
+	}
+	if c.DryRun {
+		return nil, nil
+	}
+
+	page, err := backlog.Call[*Page](ctx, c.Client, http.MethodDelete, fmt.Sprintf("/api/v2/wikis/%d", id), nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete wiki page: %w", err)
 	}
 
 	return page, nil
@@ -127,80 +226,127 @@ func (c *Client) Get(id int64) (*Page, error) {
 
 // Rename renames a wiki page.
 func (c *Client) Rename(page *Page, before, after string) error {
-	if page == nil {
-		return errors.New("empty wiki page")
+	return c.RenameContext(context.Background(), page, before, after)
+}
+
+// RenameContext is like Rename but uses the specified context for the request.
+func (c *Client) RenameContext(ctx context.Context, page *Page, before, after string) error {
+	ch, err := PlanRename(page, before, after)
+	if err != nil {
+		return err
 	}
-	if before == "" {
-		return errors.New("old strings must not be empty")
+	if err := c.ApplyContext(ctx, ch); err != nil {
+		return err
 	}
 
-	oldName := page.Name
-	newName := strings.ReplaceAll(page.Name, before, after)
+	_, _ = fmt.Fprint(c.Writer, ch.Report(c.DryRun))
+	return nil
+}
 
-	values := url.Values{
-		"name": {newName},
-	}
+// Replace replaces strings in the wiki page content.
+func (c *Client) Replace(page *Page, pairs ...string) error {
+	return c.ReplaceContext(context.Background(), page, pairs...)
+}
 
-	uri := fmt.Sprintf("%s/api/v2/wikis/%d?apiKey=%s", c.BaseURL, page.ID, c.APIKey)
-	req, err := http.NewRequest(http.MethodPatch, uri, strings.NewReader(values.Encode()))
+// ReplaceContext is like Replace but uses the specified context for the request.
+func (c *Client) ReplaceContext(ctx context.Context, page *Page, pairs ...string) error {
+	ch, err := PlanReplace(page, pairs...)
 	if err != nil {
 		return err
 	}
-	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
+	if err := c.ApplyContext(ctx, ch); err != nil {
 		return err
 	}
 
-	//nolint:errcheck
-	defer resp.Body.Close()
+	_, _ = fmt.Fprint(c.Writer, ch.Report(c.DryRun))
+	return nil
+}
+
+// ReplaceRegexp replaces regular expressions in the wiki page content.
+func (c *Client) ReplaceRegexp(page *Page, r *RegexpReplacer) error {
+	return c.ReplaceRegexpContext(context.Background(), page, r)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		msg := backlog.GetErrorMessage(resp)
-		return fmt.Errorf("failed to update wiki page: %d: %s", resp.StatusCode, msg)
+// ReplaceRegexpContext is like ReplaceRegexp but uses the specified context for the request.
+func (c *Client) ReplaceRegexpContext(ctx context.Context, page *Page, r *RegexpReplacer) error {
+	ch, err := PlanReplaceRegexp(page, r)
+	if err != nil {
+		return err
+	}
+	if err := c.ApplyContext(ctx, ch); err != nil {
+		return err
 	}
 
-	_, _ = fmt.Fprintf(c.Writer, "updated: %s => %s\n", oldName, newName)
+	_, _ = fmt.Fprint(c.Writer, ch.Report(c.DryRun))
 	return nil
 }
 
-// Replace replaces strings in the wiki page content.
-func (c *Client) Replace(page *Page, pairs ...string) error {
-	if page == nil {
+// Apply sends the change to Backlog. It sends nothing if DryRun is set.
+func (c *Client) Apply(ch *Change) error {
+	return c.ApplyContext(context.Background(), ch)
+}
+
+// ApplyContext is like Apply but uses the specified context for the request.
+func (c *Client) ApplyContext(ctx context.Context, ch *Change) error {
+	if ch == nil || ch.Page == nil {
 		return errors.New("empty wiki page")
 	}
-	if len(pairs) == 0 || len(pairs)%2 != 0 {
-		return fmt.Errorf("number of old/new strings to replace does not match: %d", len(pairs))
+	if ch.Name == nil && ch.Content == nil {
+		return errors.New("no fields to update")
 	}
-
-	replacer := strings.NewReplacer(pairs...)
-	newContent := replacer.Replace(page.Content)
-
-	values := url.Values{
-		"content": {newContent},
+	if c.DryRun {
+		return nil
 	}
 
-	uri := fmt.Sprintf("%s/api/v2/wikis/%d?apiKey=%s", c.BaseURL, page.ID, c.APIKey)
-	req, err := http.NewRequest(http.MethodPatch, uri, strings.NewReader(values.Encode()))
-	if err != nil {
+	if _, err := c.UpdateContext(ctx, ch.Page.ID, &UpdateInput{Name: ch.Name, Content: ch.Content, MailNotify: ch.MailNotify}); err != nil {
 		return err
 	}
-	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return err
+	// The change is recorded only once it is made, so that a rollback does not touch pages that failed.
+	if c.Journal != nil {
+		if err := c.Journal.Record(ch); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	//nolint:errcheck
-	defer resp.Body.Close()
+// Rollback restores the old values recorded in the journal entries.
+// Entries are reverted in reverse order so that a page changed more than once gets its oldest values.
+func (c *Client) Rollback(entries []*Entry) error {
+	return c.RollbackContext(context.Background(), entries)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		msg := backlog.GetErrorMessage(resp)
-		return fmt.Errorf("failed to update wiki page content: %d: %s", resp.StatusCode, msg)
+// RollbackContext is like Rollback but uses the specified context for the requests.
+func (c *Client) RollbackContext(ctx context.Context, entries []*Entry) error {
+	for i := len(entries) - 1; i >= 0; i-- {
+		ch := entries[i].Revert()
+		if err := c.ApplyContext(ctx, ch); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprint(c.Writer, ch.Report(c.DryRun))
 	}
-
-	_, _ = fmt.Fprintf(c.Writer, "updated: %d: %s\n", page.ID, page.Name)
 	return nil
 }
+
+func (in *CreateInput) values() url.Values {
+	return url.Values{
+		"projectId":  {strconv.FormatInt(in.ProjectID, 10)},
+		"name":       {in.Name},
+		"content":    {in.Content},
+		"mailNotify": {strconv.FormatBool(in.MailNotify)},
+	}
+}
+
+func (in *UpdateInput) values() url.Values {
+	v := url.Values{}
+	if in.Name != nil {
+		v.Set("name", *in.Name)
+	}
+	if in.Content != nil {
+		v.Set("content", *in.Content)
+	}
+	if in.MailNotify {
+		v.Set("mailNotify", "true")
+	}
+	return v
+}
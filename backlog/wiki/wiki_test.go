@@ -1,10 +1,14 @@
 package wiki
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/jarcoal/httpmock"
 	"github.com/nekrassov01/backlog-utils/backlog"
@@ -38,7 +42,7 @@ func TestNewClient(t *testing.T) {
 			},
 			expected: expected{
 				value: &Client{
-					&backlog.Client{
+					Client: &backlog.Client{
 						Writer:  io.Discard,
 						BaseURL: "https://example.com",
 						APIKey:  "dummy",
@@ -95,6 +99,7 @@ func TestWiki_List(t *testing.T) {
 	type args struct {
 		projectKey string
 		pattern    string
+		tags       []string
 	}
 	type expected struct {
 		value   []*Page
@@ -262,6 +267,36 @@ func TestWiki_List(t *testing.T) {
 				body:   "",
 			},
 		},
+		{
+			name: "tags",
+			fields: fields{
+				Backlog: &backlog.Client{
+					Writer:     io.Discard,
+					BaseURL:    "https://example.com",
+					APIKey:     "dummy",
+					HTTPClient: &http.Client{},
+				},
+			},
+			args: args{
+				projectKey: "dummy",
+				pattern:    "^Design",
+				tags:       []string{"api", "draft"},
+			},
+			expected: expected{
+				value: []*Page{
+					{ID: 1, ProjectID: 123, Name: "Design/API", Tags: []*Tag{{ID: 1, Name: "api"}}},
+					{ID: 3, ProjectID: 123, Name: "Design/UI", Tags: []*Tag{{ID: 3, Name: "ui"}, {ID: 2, Name: "draft"}}},
+				},
+				isError: false,
+			},
+			mock: mock{
+				status: 200,
+				body: `[{"id":1,"projectId":123,"name":"Design/API","tags":[{"id":1,"name":"api"}]},` +
+					`{"id":2,"projectId":123,"name":"Design/DB"},` +
+					`{"id":3,"projectId":123,"name":"Design/UI","tags":[{"id":3,"name":"ui"},{"id":2,"name":"draft"}]},` +
+					`{"id":4,"projectId":123,"name":"Notes","tags":[{"id":1,"name":"api"}]}]`,
+			},
+		},
 		{
 			name: "api error",
 			fields: fields{
@@ -323,7 +358,7 @@ func TestWiki_List(t *testing.T) {
 					httpmock.NewStringResponder(tt.mock.status, tt.mock.body),
 				)
 			}
-			actual, err := o.List(tt.args.projectKey, tt.args.pattern)
+			actual, err := o.List(tt.args.projectKey, tt.args.pattern, tt.args.tags...)
 			if tt.expected.isError {
 				assert.Error(t, err)
 				return
@@ -907,3 +942,932 @@ func TestWiki_Replace(t *testing.T) {
 		})
 	}
 }
+
+func TestWiki_ListContext(t *testing.T) {
+	type args struct {
+		ctx        context.Context
+		projectKey string
+	}
+	type expected struct {
+		value   []*Page
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "basic",
+			args: args{
+				ctx:        context.Background(),
+				projectKey: "dummy",
+			},
+			expected: expected{
+				value: []*Page{
+					{
+						ID:        1,
+						ProjectID: 123,
+						Name:      "Test Page",
+					},
+				},
+				isError: false,
+			},
+		},
+		{
+			name: "canceled context",
+			args: args{
+				ctx:        canceledContext(),
+				projectKey: "dummy",
+			},
+			expected: expected{
+				value:   nil,
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Client{
+				Client: &backlog.Client{
+					Writer:     io.Discard,
+					BaseURL:    "https://example.com",
+					APIKey:     "dummy",
+					HTTPClient: &http.Client{},
+				},
+			}
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				fmt.Sprintf("%s/api/v2/wikis?projectIdOrKey=%s&apiKey=%s", o.BaseURL, tt.args.projectKey, o.APIKey),
+				newContextResponder(200, `[{"id":1,"projectId":123,"name":"Test Page"}]`),
+			)
+			actual, err := o.ListContext(tt.args.ctx, tt.args.projectKey, "")
+			if tt.expected.isError {
+				assert.ErrorIs(t, err, context.Canceled)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.value, actual)
+		})
+	}
+}
+
+func TestWiki_GetContext(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		id  int64
+	}
+	type expected struct {
+		value   *Page
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "basic",
+			args: args{
+				ctx: context.Background(),
+				id:  1,
+			},
+			expected: expected{
+				value: &Page{
+					ID:        1,
+					ProjectID: 123,
+					Name:      "Test Page",
+					Content:   "Sample Content",
+				},
+				isError: false,
+			},
+		},
+		{
+			name: "canceled context",
+			args: args{
+				ctx: canceledContext(),
+				id:  1,
+			},
+			expected: expected{
+				value:   nil,
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Client{
+				Client: &backlog.Client{
+					Writer:     io.Discard,
+					BaseURL:    "https://example.com",
+					APIKey:     "dummy",
+					HTTPClient: &http.Client{},
+				},
+			}
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				fmt.Sprintf("%s/api/v2/wikis/%d?apiKey=%s", o.BaseURL, tt.args.id, o.APIKey),
+				newContextResponder(200, `{"id":1,"projectId":123,"name":"Test Page","content":"Sample Content"}`),
+			)
+			actual, err := o.GetContext(tt.args.ctx, tt.args.id)
+			if tt.expected.isError {
+				assert.ErrorIs(t, err, context.Canceled)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.value, actual)
+		})
+	}
+}
+
+func TestWiki_RenameContext(t *testing.T) {
+	type args struct {
+		ctx context.Context
+	}
+	type expected struct {
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "basic",
+			args: args{
+				ctx: context.Background(),
+			},
+			expected: expected{
+				isError: false,
+			},
+		},
+		{
+			name: "canceled context",
+			args: args{
+				ctx: canceledContext(),
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Client{
+				Client: &backlog.Client{
+					Writer:     io.Discard,
+					BaseURL:    "https://example.com",
+					APIKey:     "dummy",
+					HTTPClient: &http.Client{},
+				},
+			}
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			httpmock.RegisterResponder(
+				http.MethodPatch,
+				fmt.Sprintf("%s/api/v2/wikis/%d?apiKey=%s", o.BaseURL, 1, o.APIKey),
+				newContextResponder(200, ""),
+			)
+			err := o.RenameContext(tt.args.ctx, &Page{ID: 1, Name: "Old Name"}, "Old", "New")
+			if tt.expected.isError {
+				assert.ErrorIs(t, err, context.Canceled)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestWiki_ReplaceContext(t *testing.T) {
+	type args struct {
+		ctx context.Context
+	}
+	type expected struct {
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "basic",
+			args: args{
+				ctx: context.Background(),
+			},
+			expected: expected{
+				isError: false,
+			},
+		},
+		{
+			name: "canceled context",
+			args: args{
+				ctx: canceledContext(),
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Client{
+				Client: &backlog.Client{
+					Writer:     io.Discard,
+					BaseURL:    "https://example.com",
+					APIKey:     "dummy",
+					HTTPClient: &http.Client{},
+				},
+			}
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			httpmock.RegisterResponder(
+				http.MethodPatch,
+				fmt.Sprintf("%s/api/v2/wikis/%d?apiKey=%s", o.BaseURL, 1, o.APIKey),
+				newContextResponder(200, ""),
+			)
+			err := o.ReplaceContext(tt.args.ctx, &Page{ID: 1, Content: "Hello Old World"}, "Old", "New")
+			if tt.expected.isError {
+				assert.ErrorIs(t, err, context.Canceled)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestWiki_ReplaceRegexp(t *testing.T) {
+	type args struct {
+		page  *Page
+		pairs []string
+	}
+	type expected struct {
+		body    string
+		output  string
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "basic",
+			args: args{
+				page:  &Page{ID: 1, Name: "Page", Content: "#ISSUE-1 #ISSUE-2"},
+				pairs: []string{`#ISSUE-(\d+)`, "#PROJ-$1"},
+			},
+			expected: expected{
+				body:    "content=%23PROJ-1+%23PROJ-2",
+				output:  "updated: 1: Page (2 matches)\n",
+				isError: false,
+			},
+		},
+		{
+			name: "empty page",
+			args: args{
+				page:  nil,
+				pairs: []string{"Old", "New"},
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			o := &Client{
+				Client: &backlog.Client{
+					Writer:     buf,
+					BaseURL:    "https://example.com",
+					APIKey:     "dummy",
+					HTTPClient: &http.Client{},
+				},
+			}
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			var body string
+			httpmock.RegisterResponder(
+				http.MethodPatch,
+				fmt.Sprintf("%s/api/v2/wikis/%d?apiKey=%s", o.BaseURL, 1, o.APIKey),
+				func(req *http.Request) (*http.Response, error) {
+					b, err := io.ReadAll(req.Body)
+					if err != nil {
+						return nil, err
+					}
+					body = string(b)
+					return httpmock.NewStringResponse(200, ""), nil
+				},
+			)
+			r, err := NewRegexpReplacer(RegexpOptions{}, tt.args.pairs...)
+			assert.NoError(t, err)
+			err = o.ReplaceRegexp(tt.args.page, r)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.body, body)
+			assert.Equal(t, tt.expected.output, buf.String())
+		})
+	}
+}
+
+func TestWiki_Rollback(t *testing.T) {
+	type args struct {
+		entries []*Entry
+	}
+	type expected struct {
+		bodies  []string
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "reverse order",
+			args: args{
+				entries: []*Entry{
+					{PageID: 1, OldName: new("A"), NewName: new("B")},
+					{PageID: 1, OldName: new("B"), NewName: new("C")},
+				},
+			},
+			expected: expected{
+				bodies:  []string{"name=B", "name=A"},
+				isError: false,
+			},
+		},
+		{
+			name: "no fields",
+			args: args{
+				entries: []*Entry{
+					{PageID: 1},
+				},
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Client{
+				Client: &backlog.Client{
+					Writer:     io.Discard,
+					BaseURL:    "https://example.com",
+					APIKey:     "dummy",
+					HTTPClient: &http.Client{},
+				},
+			}
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			var bodies []string
+			httpmock.RegisterResponder(
+				http.MethodPatch,
+				fmt.Sprintf("%s/api/v2/wikis/%d?apiKey=%s", o.BaseURL, 1, o.APIKey),
+				func(req *http.Request) (*http.Response, error) {
+					b, err := io.ReadAll(req.Body)
+					if err != nil {
+						return nil, err
+					}
+					bodies = append(bodies, string(b))
+					return httpmock.NewStringResponse(200, ""), nil
+				},
+			)
+			err := o.Rollback(tt.args.entries)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.bodies, bodies)
+		})
+	}
+}
+
+func TestWiki_ApplyJournal(t *testing.T) {
+	o := &Client{
+		Client: &backlog.Client{
+			Writer:     io.Discard,
+			BaseURL:    "https://example.com",
+			APIKey:     "dummy",
+			HTTPClient: &http.Client{},
+		},
+	}
+	buf := &bytes.Buffer{}
+	o.Journal = NewJournal(buf)
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(
+		http.MethodPatch,
+		fmt.Sprintf("%s/api/v2/wikis/%d?apiKey=%s", o.BaseURL, 1, o.APIKey),
+		httpmock.NewStringResponder(200, ""),
+	)
+	assert.NoError(t, o.Apply(&Change{Page: &Page{ID: 1, Name: "Old"}, Name: new("New")}))
+	entries, err := ReadJournal(buf)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "Old", *entries[0].OldName)
+
+	o.DryRun = true
+	assert.NoError(t, o.Apply(&Change{Page: &Page{ID: 1, Name: "Old"}, Name: new("New")}))
+	assert.Empty(t, buf.String())
+
+	// A change that fails is not recorded, so that it is not rolled back.
+	o.DryRun = false
+	httpmock.RegisterResponder(
+		http.MethodPatch,
+		fmt.Sprintf("%s/api/v2/wikis/%d?apiKey=%s", o.BaseURL, 2, o.APIKey),
+		httpmock.NewStringResponder(400, `{"errors":[{"message":"error"}]}`),
+	)
+	assert.Error(t, o.Apply(&Change{Page: &Page{ID: 2, Name: "Old"}, Name: new("New")}))
+	assert.Empty(t, buf.String())
+}
+
+func TestWiki_ApplyRetry(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	o := &Client{
+		Client: &backlog.Client{
+			Writer:  io.Discard,
+			BaseURL: "https://example.com",
+			APIKey:  "dummy",
+			HTTPClient: &http.Client{
+				Transport: backlog.NewRetryableTransport(time.Millisecond, time.Millisecond, 2, 1),
+			},
+		},
+	}
+	httpmock.RegisterResponder(
+		http.MethodPatch,
+		fmt.Sprintf("%s/api/v2/wikis/%d?apiKey=%s", o.BaseURL, 1, o.APIKey),
+		httpmock.NewErrorResponder(syscall.ECONNRESET).Then(httpmock.NewStringResponder(200, "")),
+	)
+	assert.NoError(t, o.Apply(&Change{Page: &Page{ID: 1, Name: "Old"}, Name: new("New")}))
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+}
+
+func newContextResponder(status int, body string) httpmock.Responder {
+	return func(req *http.Request) (*http.Response, error) {
+		if err := req.Context().Err(); err != nil {
+			return nil, err
+		}
+		return httpmock.NewStringResponse(status, body), nil
+	}
+}
+
+func canceledContext() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return ctx
+}
+
+func TestWiki_Apply(t *testing.T) {
+	name := "New Name"
+	content := "New Content"
+	type fields struct {
+		DryRun bool
+	}
+	type args struct {
+		change *Change
+	}
+	type expected struct {
+		body    string
+		calls   int
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		expected expected
+	}{
+		{
+			name: "basic",
+			fields: fields{
+				DryRun: false,
+			},
+			args: args{
+				change: &Change{Page: &Page{ID: 1, Name: "Old Name"}, Name: &name, Content: &content},
+			},
+			expected: expected{
+				body:    "content=New+Content&name=New+Name",
+				calls:   1,
+				isError: false,
+			},
+		},
+		{
+			name: "dry run",
+			fields: fields{
+				DryRun: true,
+			},
+			args: args{
+				change: &Change{Page: &Page{ID: 1, Name: "Old Name"}, Name: &name},
+			},
+			expected: expected{
+				calls:   0,
+				isError: false,
+			},
+		},
+		{
+			name: "empty change",
+			fields: fields{
+				DryRun: false,
+			},
+			args: args{
+				change: nil,
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name: "no fields",
+			fields: fields{
+				DryRun: false,
+			},
+			args: args{
+				change: &Change{Page: &Page{ID: 1}},
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Client{
+				Client: &backlog.Client{
+					Writer:     io.Discard,
+					BaseURL:    "https://example.com",
+					APIKey:     "dummy",
+					HTTPClient: &http.Client{},
+				},
+				DryRun: tt.fields.DryRun,
+			}
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			var body string
+			httpmock.RegisterResponder(
+				http.MethodPatch,
+				fmt.Sprintf("%s/api/v2/wikis/%d?apiKey=%s", o.BaseURL, 1, o.APIKey),
+				func(req *http.Request) (*http.Response, error) {
+					b, err := io.ReadAll(req.Body)
+					if err != nil {
+						return nil, err
+					}
+					body = string(b)
+					return httpmock.NewStringResponse(200, ""), nil
+				},
+			)
+			err := o.Apply(tt.args.change)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.body, body)
+			assert.Equal(t, tt.expected.calls, httpmock.GetTotalCallCount())
+		})
+	}
+}
+
+func TestWiki_Create(t *testing.T) {
+	type fields struct {
+		DryRun bool
+	}
+	type args struct {
+		in *CreateInput
+	}
+	type expected struct {
+		value   *Page
+		body    string
+		calls   int
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		expected expected
+	}{
+		{
+			name: "basic",
+			args: args{
+				in: &CreateInput{ProjectID: 123, Name: "Design/API", Content: "# API"},
+			},
+			expected: expected{
+				value: &Page{ID: 1, ProjectID: 123, Name: "Design/API", Content: "# API"},
+				body:  "content=%23+API&mailNotify=false&name=Design%2FAPI&projectId=123",
+				calls: 1,
+			},
+		},
+		{
+			name: "mail notify",
+			args: args{
+				in: &CreateInput{ProjectID: 123, Name: "Design/API", MailNotify: true},
+			},
+			expected: expected{
+				value: &Page{ID: 1, ProjectID: 123, Name: "Design/API", Content: "# API"},
+				body:  "content=&mailNotify=true&name=Design%2FAPI&projectId=123",
+				calls: 1,
+			},
+		},
+		{
+			name: "dry run",
+			fields: fields{
+				DryRun: true,
+			},
+			args: args{
+				in: &CreateInput{ProjectID: 123, Name: "Design/API"},
+			},
+			expected: expected{
+				calls: 0,
+			},
+		},
+		{
+			name: "empty input",
+			args: args{
+				in: nil,
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name: "invalid project id",
+			args: args{
+				in: &CreateInput{ProjectID: 0, Name: "Design/API"},
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name: "empty name",
+			args: args{
+				in: &CreateInput{ProjectID: 123, Name: ""},
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Client{
+				Client: &backlog.Client{
+					Writer:     io.Discard,
+					BaseURL:    "https://example.com",
+					APIKey:     "dummy",
+					HTTPClient: &http.Client{},
+				},
+				DryRun: tt.fields.DryRun,
+			}
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			var body string
+			httpmock.RegisterResponder(
+				http.MethodPost,
+				fmt.Sprintf("%s/api/v2/wikis?apiKey=%s", o.BaseURL, o.APIKey),
+				func(req *http.Request) (*http.Response, error) {
+					b, err := io.ReadAll(req.Body)
+					if err != nil {
+						return nil, err
+					}
+					body = string(b)
+					return httpmock.NewStringResponse(201, `{"id":1,"projectId":123,"name":"Design/API","content":"# API"}`), nil
+				},
+			)
+			actual, err := o.Create(tt.args.in)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.value, actual)
+			assert.Equal(t, tt.expected.body, body)
+			assert.Equal(t, tt.expected.calls, httpmock.GetTotalCallCount())
+		})
+	}
+}
+
+func TestWiki_Update(t *testing.T) {
+	type fields struct {
+		DryRun bool
+	}
+	type args struct {
+		id int64
+		in *UpdateInput
+	}
+	type expected struct {
+		value   *Page
+		body    string
+		calls   int
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		expected expected
+	}{
+		{
+			name: "name and content",
+			args: args{
+				id: 1,
+				in: &UpdateInput{Name: new("New Name"), Content: new("New Content")},
+			},
+			expected: expected{
+				value: &Page{ID: 1, ProjectID: 123, Name: "New Name", Content: "New Content"},
+				body:  "content=New+Content&name=New+Name",
+				calls: 1,
+			},
+		},
+		{
+			name: "content with mail notify",
+			args: args{
+				id: 1,
+				in: &UpdateInput{Content: new("New Content"), MailNotify: true},
+			},
+			expected: expected{
+				value: &Page{ID: 1, ProjectID: 123, Name: "New Name", Content: "New Content"},
+				body:  "content=New+Content&mailNotify=true",
+				calls: 1,
+			},
+		},
+		{
+			name: "dry run",
+			fields: fields{
+				DryRun: true,
+			},
+			args: args{
+				id: 1,
+				in: &UpdateInput{Name: new("New Name")},
+			},
+			expected: expected{
+				calls: 0,
+			},
+		},
+		{
+			name: "no fields",
+			args: args{
+				id: 1,
+				in: &UpdateInput{MailNotify: true},
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name: "empty input",
+			args: args{
+				id: 1,
+				in: nil,
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name: "invalid id",
+			args: args{
+				id: 0,
+				in: &UpdateInput{Name: new("New Name")},
+			},
+			expected: expected{
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Client{
+				Client: &backlog.Client{
+					Writer:     io.Discard,
+					BaseURL:    "https://example.com",
+					APIKey:     "dummy",
+					HTTPClient: &http.Client{},
+				},
+				DryRun: tt.fields.DryRun,
+			}
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			var body string
+			httpmock.RegisterResponder(
+				http.MethodPatch,
+				fmt.Sprintf("%s/api/v2/wikis/%d?apiKey=%s", o.BaseURL, tt.args.id, o.APIKey),
+				func(req *http.Request) (*http.Response, error) {
+					b, err := io.ReadAll(req.Body)
+					if err != nil {
+						return nil, err
+					}
+					body = string(b)
+					return httpmock.NewStringResponse(200, `{"id":1,"projectId":123,"name":"New Name","content":"New Content"}`), nil
+				},
+			)
+			actual, err := o.Update(tt.args.id, tt.args.in)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.value, actual)
+			assert.Equal(t, tt.expected.body, body)
+			assert.Equal(t, tt.expected.calls, httpmock.GetTotalCallCount())
+		})
+	}
+}
+
+func TestWiki_Delete(t *testing.T) {
+	type fields struct {
+		DryRun bool
+	}
+	type expected struct {
+		value   *Page
+		calls   int
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		id       int64
+		status   int
+		expected expected
+	}{
+		{
+			name:   "basic",
+			id:     1,
+			status: 200,
+			expected: expected{
+				value: &Page{ID: 1, ProjectID: 123, Name: "Old"},
+				calls: 1,
+			},
+		},
+		{
+			name: "dry run",
+			fields: fields{
+				DryRun: true,
+			},
+			id: 1,
+			expected: expected{
+				calls: 0,
+			},
+		},
+		{
+			name:   "not found",
+			id:     1,
+			status: 404,
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name: "invalid id",
+			id:   0,
+			expected: expected{
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Client{
+				Client: &backlog.Client{
+					Writer:     io.Discard,
+					BaseURL:    "https://example.com",
+					APIKey:     "dummy",
+					HTTPClient: &http.Client{},
+				},
+				DryRun: tt.fields.DryRun,
+			}
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			httpmock.RegisterResponder(
+				http.MethodDelete,
+				fmt.Sprintf("%s/api/v2/wikis/%d?apiKey=%s", o.BaseURL, tt.id, o.APIKey),
+				httpmock.NewStringResponder(tt.status, `{"id":1,"projectId":123,"name":"Old"}`),
+			)
+			actual, err := o.Delete(tt.id)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.value, actual)
+			assert.Equal(t, tt.expected.calls, httpmock.GetTotalCallCount())
+		})
+	}
+}
+
+func TestPageError(t *testing.T) {
+	err := &PageError{PageID: 1, Err: context.Canceled}
+	assert.Equal(t, "wiki page 1: context canceled", err.Error())
+	assert.ErrorIs(t, err, context.Canceled)
+}
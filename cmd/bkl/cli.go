@@ -3,12 +3,24 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/nekrassov01/backlog-utils/backlog"
+	"github.com/nekrassov01/backlog-utils/backlog/issue"
+	"github.com/nekrassov01/backlog-utils/backlog/oauth"
+	"github.com/nekrassov01/backlog-utils/backlog/project"
 	"github.com/nekrassov01/backlog-utils/backlog/wiki"
+	"github.com/nekrassov01/backlog-utils/config"
 	"github.com/nekrassov01/backlog-utils/log"
 	"github.com/nekrassov01/backlog-utils/version"
 	"github.com/urfave/cli/v3"
@@ -16,11 +28,70 @@ import (
 
 const name = "bkl"
 
+var errSkipped = errors.New("skipped after an earlier failure")
+
+// errInterrupted marks the errors of the items of a bulk command that were in flight when it was canceled,
+// whose requests may or may not have been processed.
+var errInterrupted = errors.New("interrupted while in flight")
+
 var logger = &slog.Logger{}
 
 func newCmd(w, ew io.Writer) *cli.Command {
 	logger = log.NewLogger(ew, slog.LevelInfo.String())
 
+	profile := &cli.StringFlag{
+		Name:    "profile",
+		Usage:   "set profile in config file to use (default: default_profile in config file)",
+		Sources: cli.EnvVars("BACKLOG_PROFILE"),
+	}
+
+	configFile := &cli.StringFlag{
+		Name:    "config",
+		Usage:   "set config file (default: <user config dir>/bkl/config.toml)",
+		Sources: cli.EnvVars("BACKLOG_CONFIG"),
+	}
+
+	retryInitialInterval := &cli.DurationFlag{
+		Name:  "retry-initial-interval",
+		Usage: "set initial interval of exponential backoff between retries",
+		Value: 1 * time.Second,
+	}
+
+	retryMaxInterval := &cli.DurationFlag{
+		Name:  "retry-max-interval",
+		Usage: "set maximum interval of exponential backoff between retries",
+		Value: 30 * time.Second,
+	}
+
+	retryMaxAttempts := &cli.IntFlag{
+		Name:  "retry-max-attempts",
+		Usage: "set maximum number of attempts per request",
+		Value: 5,
+	}
+
+	retryMaxJitter := &cli.IntFlag{
+		Name:  "retry-max-jitter-ms",
+		Usage: "set maximum random jitter in milliseconds added to the interval between retries, or 0 to disable it",
+		Value: 3000,
+	}
+
+	retryStatus := &cli.IntSliceFlag{
+		Name:  "retry-status",
+		Usage: "set response status codes to retry",
+		Value: backlog.DefaultRetryPolicy().Statuses,
+	}
+
+	retryNetworkErrors := &cli.BoolFlag{
+		Name:  "retry-network-errors",
+		Usage: "retry idempotent requests that failed with transient network errors such as timeouts and connection resets",
+		Value: true,
+	}
+
+	retryIdempotentOnly := &cli.BoolFlag{
+		Name:  "retry-idempotent-only",
+		Usage: "retry responses with the statuses only for idempotent requests except for 429 responses",
+	}
+
 	loglevel := &cli.StringFlag{
 		Name:    "log-level",
 		Usage:   "set log level",
@@ -36,10 +107,34 @@ func newCmd(w, ew io.Writer) *cli.Command {
 
 	apiKey := &cli.StringFlag{
 		Name:    "api-key",
-		Usage:   "set backlog api key",
+		Usage:   "set backlog api key, which is sent in the query string, so prefer oauth to keep it out of access logs",
 		Sources: cli.EnvVars("BACKLOG_API_KEY"),
 	}
 
+	clientID := &cli.StringFlag{
+		Name:    "client-id",
+		Usage:   "set oauth client id used instead of api key",
+		Sources: cli.EnvVars("BACKLOG_CLIENT_ID"),
+	}
+
+	clientSecret := &cli.StringFlag{
+		Name:    "client-secret",
+		Usage:   "set oauth client secret",
+		Sources: cli.EnvVars("BACKLOG_CLIENT_SECRET"),
+	}
+
+	tokenFile := &cli.StringFlag{
+		Name:    "token-file",
+		Usage:   "set file to store oauth token (default: <user config dir>/bkl/token.json)",
+		Sources: cli.EnvVars("BACKLOG_TOKEN_FILE"),
+	}
+
+	redirectURL := &cli.StringFlag{
+		Name:  "redirect-url",
+		Usage: "set oauth redirect url registered for the client, which must be a loopback address",
+		Value: "http://localhost:8080/callback",
+	}
+
 	projectKey := &cli.StringFlag{
 		Name:     "project-key",
 		Usage:    "set backlog project key",
@@ -51,12 +146,53 @@ func newCmd(w, ew io.Writer) *cli.Command {
 		Usage: "set pattern to search for wiki pages",
 	}
 
-	wikiID := &cli.IntFlag{
+	tagFilter := &cli.StringSliceFlag{
+		Name:  "tag",
+		Usage: "set tags to filter wiki pages, matching pages with any of them",
+	}
+
+	addTags := &cli.StringSliceFlag{
+		Name:  "add",
+		Usage: "set tags to add to wiki pages",
+	}
+
+	removeTags := &cli.StringSliceFlag{
+		Name:  "remove",
+		Usage: "set tags to remove from wiki pages",
+	}
+
+	wikiID := &cli.Int64Flag{
 		Name:     "wiki-id",
 		Usage:    "set backlog wiki id",
 		Required: true,
 	}
 
+	pageName := &cli.StringFlag{
+		Name:     "name",
+		Usage:    "set wiki page name",
+		Required: true,
+	}
+
+	newName := &cli.StringFlag{
+		Name:  "name",
+		Usage: "set new wiki page name",
+	}
+
+	content := &cli.StringFlag{
+		Name:  "content",
+		Usage: "set wiki page content",
+	}
+
+	contentFile := &cli.StringFlag{
+		Name:  "content-file",
+		Usage: "set file to read wiki page content from, or - to read from stdin",
+	}
+
+	mailNotify := &cli.BoolFlag{
+		Name:  "mail-notify",
+		Usage: "send notification mail of the change",
+	}
+
 	oldString := &cli.StringFlag{
 		Name:     "old",
 		Usage:    "set string to be replaced in wiki page",
@@ -75,16 +211,420 @@ func newCmd(w, ew io.Writer) *cli.Command {
 		Required: true,
 	}
 
-	beforeWiki := func(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+	regex := &cli.BoolFlag{
+		Name:  "regex",
+		Usage: "treat pairs as regular expressions and replacement templates",
+	}
+
+	multiline := &cli.BoolFlag{
+		Name:  "multiline",
+		Usage: "make ^ and $ match at line boundaries in regex mode",
+	}
+
+	ignoreCase := &cli.BoolFlag{
+		Name:  "ignore-case",
+		Usage: "match case-insensitively in regex mode",
+	}
+
+	dryRun := &cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "show changes without updating wiki pages",
+	}
+
+	journal := &cli.StringFlag{
+		Name:  "journal",
+		Usage: "set journal file to record old values of wiki pages (default: bkl-journal-<time>.jsonl in the current directory, created when the first page is changed)",
+	}
+
+	rollbackJournal := &cli.StringFlag{
+		Name:     "journal",
+		Usage:    "set journal file to restore wiki pages from",
+		Required: true,
+	}
+
+	concurrency := &cli.IntFlag{
+		Name:  "concurrency",
+		Usage: "set number of wiki pages processed concurrently",
+		Value: 1,
+	}
+
+	continueOnError := &cli.BoolFlag{
+		Name:  "continue-on-error",
+		Usage: "continue processing the remaining wiki pages after a failure",
+	}
+
+	reportFile := &cli.StringFlag{
+		Name:  "report",
+		Usage: "set file to write a json report of succeeded, failed, interrupted and skipped wiki pages",
+	}
+
+	outDir := &cli.StringFlag{
+		Name:     "out",
+		Usage:    "set directory to export wiki pages to as markdown files",
+		Required: true,
+	}
+
+	prune := &cli.BoolFlag{
+		Name:  "prune",
+		Usage: "delete wiki pages that do not exist in the directory",
+	}
+
+	historyOrder := &cli.StringFlag{
+		Name:  "order",
+		Usage: "set order of revisions (asc or desc)",
+		Value: "desc",
+	}
+
+	historyMaxItems := &cli.IntFlag{
+		Name:  "max-items",
+		Usage: "set maximum number of revisions to list (0 means no limit)",
+	}
+
+	fromVersion := &cli.Int64Flag{
+		Name:     "from",
+		Usage:    "set version of wiki page to diff from",
+		Required: true,
+	}
+
+	toVersion := &cli.Int64Flag{
+		Name:  "to",
+		Usage: "set version of wiki page to diff to (default: current content)",
+	}
+
+	restoreVersion := &cli.Int64Flag{
+		Name:     "version",
+		Usage:    "set version of wiki page to restore the content of",
+		Required: true,
+	}
+
+	expectedVersion := &cli.Int64Flag{
+		Name:  "expected-version",
+		Usage: "fail if the latest version of wiki page is not this one, to keep edits made after it was inspected",
+	}
+
+	attachmentIDs := &cli.Int64SliceFlag{
+		Name:  "attachment-id",
+		Usage: "set attachment ids of wiki page to download (default: all attachments)",
+	}
+
+	deleteAttachmentIDs := &cli.Int64SliceFlag{
+		Name:     "attachment-id",
+		Usage:    "set attachment ids of wiki page to delete",
+		Required: true,
+	}
+
+	attachmentDir := &cli.StringFlag{
+		Name:  "out",
+		Usage: "set directory to download attachments to",
+		Value: ".",
+	}
+
+	overwrite := &cli.BoolFlag{
+		Name:  "overwrite",
+		Usage: "replace files that already exist in the directory",
+	}
+
+	projectKeys := &cli.StringSliceFlag{
+		Name:  "project-key",
+		Usage: "set backlog project keys to filter issues",
+	}
+
+	issueKey := &cli.StringFlag{
+		Name:     "issue-key",
+		Usage:    "set backlog issue id or key",
+		Required: true,
+	}
+
+	issueTypeIDs := &cli.Int64SliceFlag{
+		Name:  "issue-type-id",
+		Usage: "set issue type ids to filter issues",
+	}
+
+	statusIDs := &cli.Int64SliceFlag{
+		Name:  "status-id",
+		Usage: "set status ids to filter issues",
+	}
+
+	priorityIDs := &cli.Int64SliceFlag{
+		Name:  "priority-id",
+		Usage: "set priority ids to filter issues",
+	}
+
+	assigneeIDs := &cli.Int64SliceFlag{
+		Name:  "assignee-id",
+		Usage: "set assignee ids to filter issues",
+	}
+
+	keyword := &cli.StringFlag{
+		Name:  "keyword",
+		Usage: "set keyword to search for issues",
+	}
+
+	dateConfig := cli.TimestampConfig{
+		Layouts: []string{"2006-01-02"},
+	}
+
+	createdSince := &cli.TimestampFlag{
+		Name:   "created-since",
+		Usage:  "set lower bound of created date (yyyy-mm-dd)",
+		Config: dateConfig,
+	}
+
+	createdUntil := &cli.TimestampFlag{
+		Name:   "created-until",
+		Usage:  "set upper bound of created date (yyyy-mm-dd)",
+		Config: dateConfig,
+	}
+
+	updatedSince := &cli.TimestampFlag{
+		Name:   "updated-since",
+		Usage:  "set lower bound of updated date (yyyy-mm-dd)",
+		Config: dateConfig,
+	}
+
+	updatedUntil := &cli.TimestampFlag{
+		Name:   "updated-until",
+		Usage:  "set upper bound of updated date (yyyy-mm-dd)",
+		Config: dateConfig,
+	}
+
+	startDateSince := &cli.TimestampFlag{
+		Name:   "start-date-since",
+		Usage:  "set lower bound of start date (yyyy-mm-dd)",
+		Config: dateConfig,
+	}
+
+	startDateUntil := &cli.TimestampFlag{
+		Name:   "start-date-until",
+		Usage:  "set upper bound of start date (yyyy-mm-dd)",
+		Config: dateConfig,
+	}
+
+	dueDateSince := &cli.TimestampFlag{
+		Name:   "due-date-since",
+		Usage:  "set lower bound of due date (yyyy-mm-dd)",
+		Config: dateConfig,
+	}
+
+	dueDateUntil := &cli.TimestampFlag{
+		Name:   "due-date-until",
+		Usage:  "set upper bound of due date (yyyy-mm-dd)",
+		Config: dateConfig,
+	}
+
+	sort := &cli.StringFlag{
+		Name:  "sort",
+		Usage: "set sort key of issues (e.g. created, updated, dueDate)",
+	}
+
+	order := &cli.StringFlag{
+		Name:  "order",
+		Usage: "set sort order of issues (asc or desc)",
+	}
+
+	offset := &cli.IntFlag{
+		Name:  "offset",
+		Usage: "set offset of issues",
+	}
+
+	count := &cli.IntFlag{
+		Name:  "count",
+		Usage: "set number of issues fetched per page (1-100)",
+		Value: backlog.DefaultPageSize,
+	}
+
+	maxItems := &cli.IntFlag{
+		Name:  "max-items",
+		Usage: "set maximum number of issues to list (0 means no limit)",
+	}
+
+	summary := &cli.StringFlag{
+		Name:  "summary",
+		Usage: "set issue summary",
+	}
+
+	description := &cli.StringFlag{
+		Name:  "description",
+		Usage: "set issue description",
+	}
+
+	issueTypeID := &cli.Int64Flag{
+		Name:  "issue-type-id",
+		Usage: "set issue type id",
+	}
+
+	statusID := &cli.Int64Flag{
+		Name:  "status-id",
+		Usage: "set status id",
+	}
+
+	priorityID := &cli.Int64Flag{
+		Name:  "priority-id",
+		Usage: "set priority id",
+	}
+
+	assigneeID := &cli.Int64Flag{
+		Name:  "assignee-id",
+		Usage: "set assignee id",
+	}
+
+	startDate := &cli.TimestampFlag{
+		Name:   "start-date",
+		Usage:  "set start date (yyyy-mm-dd)",
+		Config: dateConfig,
+	}
+
+	dueDate := &cli.TimestampFlag{
+		Name:   "due-date",
+		Usage:  "set due date (yyyy-mm-dd)",
+		Config: dateConfig,
+	}
+
+	comment := &cli.StringFlag{
+		Name:  "comment",
+		Usage: "set comment to add with the update",
+	}
+
+	oauthConfig := func(cmd *cli.Command) *oauth.Config {
+		return &oauth.Config{
+			BaseURL:      cmd.String(baseURL.Name),
+			ClientID:     cmd.String(clientID.Name),
+			ClientSecret: cmd.String(clientSecret.Name),
+			RedirectURL:  cmd.String(redirectURL.Name),
+		}
+	}
+
+	tokenStore := func(cmd *cli.Command) (*oauth.FileStore, error) {
+		path := cmd.String(tokenFile.Name)
+		if path == "" {
+			dir, err := os.UserConfigDir()
+			if err != nil {
+				return nil, err
+			}
+			path = filepath.Join(dir, name, "token.json")
+		}
+		return &oauth.FileStore{Path: path}, nil
+	}
+
+	loadConfig := func(cmd *cli.Command) (*config.Config, string, error) {
+		path := cmd.String(configFile.Name)
+		if path == "" {
+			var err error
+			path, err = config.DefaultPath()
+			if err != nil {
+				return nil, "", err
+			}
+		}
+		c, err := config.Load(path)
+		if err != nil {
+			return nil, "", err
+		}
+		return c, path, nil
+	}
+
+	// applyProfile fills the flags of the command that are not given on the command line or environment
+	// with the values of the selected profile, so that flags take precedence over the profile.
+	// This runs in Before, which precedes the check of required flags, so the profile can satisfy them.
+	applyProfile := func(cmd *cli.Command) error {
+		c, _, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		p, err := c.Profile(cmd.String(profile.Name))
+		if err != nil || p == nil {
+			return err
+		}
+		values := p.Values()
+		for _, f := range []cli.Flag{
+			loglevel, baseURL, apiKey, clientID, clientSecret, tokenFile, projectKey, projectKeys,
+			retryInitialInterval, retryMaxInterval, retryMaxAttempts, retryMaxJitter, retryStatus, retryNetworkErrors, retryIdempotentOnly,
+		} {
+			n := f.Names()[0]
+			v, ok := values[n]
+			if !ok || cmd.IsSet(n) || !slices.Contains(cmd.Flags, f) && !slices.Contains(cmd.Root().Flags, f) {
+				continue
+			}
+			if err := cmd.Set(n, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	newClient := func(cmd *cli.Command) (*backlog.Client, error) {
+		if err := applyProfile(cmd); err != nil {
+			return nil, err
+		}
+
 		logger = log.NewLogger(cmd.Writer, cmd.String(loglevel.Name))
 
-		transport := backlog.NewRetryableTransport(1*time.Second, 30*time.Second, 5, 3000)
-		client, err := wiki.NewClient(
-			cmd.String(baseURL.Name),
-			cmd.String(apiKey.Name),
+		// The values are checked after the profile is applied so that invalid values in the config file are rejected as well.
+		for _, f := range []*cli.DurationFlag{retryInitialInterval, retryMaxInterval} {
+			if d := cmd.Duration(f.Name); d < 0 {
+				return nil, fmt.Errorf("invalid %s: must not be negative: %s", f.Name, d)
+			}
+		}
+		if n := cmd.Int(retryMaxAttempts.Name); n < 1 {
+			return nil, fmt.Errorf("invalid %s: must be at least 1: %d", retryMaxAttempts.Name, n)
+		}
+		if n := cmd.Int(retryMaxJitter.Name); n < 0 {
+			return nil, fmt.Errorf("invalid %s: must not be negative: %d", retryMaxJitter.Name, n)
+		}
+
+		transport := backlog.NewRetryableTransport(
+			cmd.Duration(retryInitialInterval.Name),
+			cmd.Duration(retryMaxInterval.Name),
+			cmd.Int(retryMaxAttempts.Name),
+			cmd.Int(retryMaxJitter.Name),
+		)
+		transport.Policy = &backlog.RetryPolicy{
+			Statuses:       cmd.IntSlice(retryStatus.Name),
+			NetworkErrors:  cmd.Bool(retryNetworkErrors.Name),
+			IdempotentOnly: cmd.Bool(retryIdempotentOnly.Name),
+		}
+		// Each attempt goes through the limiter so that retries are paced as well.
+		transport.Transport = backlog.NewRateLimiter(transport.Transport)
+		transport.Observer = backlog.NewSlogObserver(logger)
+		opts := []backlog.ClientOption{
 			backlog.WithWriter(cmd.Writer),
 			backlog.WithTransport(transport),
-		)
+		}
+
+		// The API key takes precedence, and OAuth is used only when a client id is given without it.
+		if cmd.String(apiKey.Name) == "" && cmd.String(clientID.Name) != "" {
+			store, err := tokenStore(cmd)
+			if err != nil {
+				return nil, err
+			}
+			t := oauth.NewTransport(oauthConfig(cmd), store, transport)
+			opts = append(opts, backlog.WithTransport(t), backlog.WithAuthenticator(t))
+		}
+
+		return backlog.NewClient(cmd.String(baseURL.Name), cmd.String(apiKey.Name), opts...)
+	}
+
+	beforeWiki := func(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+		client, err := newClient(cmd)
+		if err != nil {
+			return nil, err
+		}
+
+		cmd.Metadata["client"] = &wiki.Client{Client: client, DryRun: cmd.Bool(dryRun.Name)}
+		return ctx, nil
+	}
+
+	beforeIssue := func(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+		client, err := newClient(cmd)
+		if err != nil {
+			return nil, err
+		}
+
+		cmd.Metadata["client"] = &issue.Client{Client: client}
+		return ctx, nil
+	}
+
+	beforeRateLimit := func(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+		client, err := newClient(cmd)
 		if err != nil {
 			return nil, err
 		}
@@ -93,11 +633,11 @@ func newCmd(w, ew io.Writer) *cli.Command {
 		return ctx, nil
 	}
 
-	listWiki := func(_ context.Context, cmd *cli.Command) error {
+	listWiki := func(ctx context.Context, cmd *cli.Command) error {
 		logger.Info("started")
 
 		client := cmd.Metadata["client"].(*wiki.Client)
-		pages, err := client.List(cmd.String(projectKey.Name), cmd.String(pattern.Name))
+		pages, err := client.ListContext(ctx, cmd.String(projectKey.Name), cmd.String(pattern.Name), cmd.StringSlice(tagFilter.Name)...)
 		if err != nil {
 			return err
 		}
@@ -113,16 +653,16 @@ func newCmd(w, ew io.Writer) *cli.Command {
 		return nil
 	}
 
-	renameWiki := func(_ context.Context, cmd *cli.Command) error {
+	renameWiki := func(ctx context.Context, cmd *cli.Command) error {
 		logger.Info("started")
 
 		client := cmd.Metadata["client"].(*wiki.Client)
-		page, err := client.Get(cmd.Int64(wikiID.Name))
+		page, err := client.GetContext(ctx, cmd.Int64(wikiID.Name))
 		if err != nil {
 			return err
 		}
 
-		if err := client.Rename(page, cmd.String(oldString.Name), cmd.String(newString.Name)); err != nil {
+		if err := client.RenameContext(ctx, page, cmd.String(oldString.Name), cmd.String(newString.Name)); err != nil {
 			return err
 		}
 
@@ -130,68 +670,1141 @@ func newCmd(w, ew io.Writer) *cli.Command {
 		return nil
 	}
 
-	replaceWiki := func(_ context.Context, cmd *cli.Command) error {
+	readContent := func(cmd *cli.Command) (*string, error) {
+		if cmd.IsSet(content.Name) && cmd.IsSet(contentFile.Name) {
+			return nil, errors.New("content and content-file cannot be set together")
+		}
+		if cmd.IsSet(content.Name) {
+			return new(cmd.String(content.Name)), nil
+		}
+		path := cmd.String(contentFile.Name)
+		if path == "" {
+			return nil, nil
+		}
+		r := cmd.Root().Reader
+		if path != "-" {
+			f, err := os.Open(filepath.Clean(path))
+			if err != nil {
+				return nil, err
+			}
+			defer func() { _ = f.Close() }()
+			r = f
+		}
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		return new(string(b)), nil
+	}
+
+	createWiki := func(ctx context.Context, cmd *cli.Command) error {
 		logger.Info("started")
 
+		body, err := readContent(cmd)
+		if err != nil {
+			return err
+		}
+
 		client := cmd.Metadata["client"].(*wiki.Client)
-		pages, err := client.List(cmd.String(projectKey.Name), cmd.String(pattern.Name))
+		p, err := (&project.Client{Client: client.Client}).GetContext(ctx, cmd.String(projectKey.Name))
 		if err != nil {
 			return err
 		}
 
-		for _, page := range pages {
-			if err := client.Replace(page, cmd.StringSlice(pairs.Name)...); err != nil {
-				return err
-			}
+		in := &wiki.CreateInput{
+			ProjectID:  p.ID,
+			Name:       cmd.String(pageName.Name),
+			MailNotify: cmd.Bool(mailNotify.Name),
+		}
+		if body != nil {
+			in.Content = *body
+		}
+		page, err := client.CreateContext(ctx, in)
+		if err != nil {
+			return err
+		}
+		if client.DryRun {
+			_, _ = fmt.Fprintf(cmd.Writer, "would create: %s\n", in.Name)
+		} else {
+			_, _ = fmt.Fprintf(cmd.Writer, "created: %d: %s\n", page.ID, page.Name)
 		}
 
 		logger.Info("stopped")
 		return nil
 	}
 
-	renameWikiAll := func(_ context.Context, cmd *cli.Command) error {
+	updateWiki := func(ctx context.Context, cmd *cli.Command) error {
 		logger.Info("started")
 
-		client := cmd.Metadata["client"].(*wiki.Client)
-		pages, err := client.List(cmd.String(projectKey.Name), cmd.String(pattern.Name))
+		body, err := readContent(cmd)
 		if err != nil {
 			return err
 		}
+		ch := &wiki.Change{Content: body, MailNotify: cmd.Bool(mailNotify.Name)}
+		if cmd.IsSet(newName.Name) {
+			ch.Name = new(cmd.String(newName.Name))
+		}
+		if ch.Name == nil && ch.Content == nil {
+			return errors.New("no fields to update: set name, content or content-file")
+		}
 
-		for _, page := range pages {
-			if err := client.Rename(page, cmd.String(oldString.Name), cmd.String(newString.Name)); err != nil {
-				return err
-			}
+		client := cmd.Metadata["client"].(*wiki.Client)
+		ch.Page, err = client.GetContext(ctx, cmd.Int64(wikiID.Name))
+		if err != nil {
+			return err
+		}
+		if err := client.ApplyContext(ctx, ch); err != nil {
+			return err
 		}
+		_, _ = fmt.Fprint(cmd.Writer, ch.Report(client.DryRun))
 
 		logger.Info("stopped")
 		return nil
 	}
 
-	replaceWikiAll := func(_ context.Context, cmd *cli.Command) error {
+	deleteWiki := func(ctx context.Context, cmd *cli.Command) error {
 		logger.Info("started")
 
 		client := cmd.Metadata["client"].(*wiki.Client)
-		pages, err := client.List(cmd.String(projectKey.Name), cmd.String(pattern.Name))
-		if err != nil {
-			return err
-		}
-
-		for _, page := range pages {
-			detail, err := client.Get(page.ID)
+		if client.DryRun {
+			page, err := client.GetContext(ctx, cmd.Int64(wikiID.Name))
 			if err != nil {
 				return err
 			}
-			if err := client.Replace(detail, cmd.StringSlice(pairs.Name)...); err != nil {
+			_, _ = fmt.Fprintf(cmd.Writer, "would delete: %d: %s\n", page.ID, page.Name)
+		} else {
+			page, err := client.DeleteContext(ctx, cmd.Int64(wikiID.Name))
+			if err != nil {
 				return err
 			}
+			_, _ = fmt.Fprintf(cmd.Writer, "deleted: %d: %s\n", page.ID, page.Name)
 		}
 
 		logger.Info("stopped")
 		return nil
 	}
 
-	return &cli.Command{
+	planReplace := func(cmd *cli.Command) (func(*wiki.Page) (*wiki.Change, error), error) {
+		if !cmd.Bool(regex.Name) {
+			return func(page *wiki.Page) (*wiki.Change, error) {
+				return wiki.PlanReplace(page, cmd.StringSlice(pairs.Name)...)
+			}, nil
+		}
+		opts := wiki.RegexpOptions{
+			Multiline:  cmd.Bool(multiline.Name),
+			IgnoreCase: cmd.Bool(ignoreCase.Name),
+		}
+		r, err := wiki.NewRegexpReplacer(opts, cmd.StringSlice(pairs.Name)...)
+		if err != nil {
+			return nil, err
+		}
+		return func(page *wiki.Page) (*wiki.Change, error) {
+			return wiki.PlanReplaceRegexp(page, r)
+		}, nil
+	}
+
+	replaceWiki := func(ctx context.Context, cmd *cli.Command) error {
+		logger.Info("started")
+
+		plan, err := planReplace(cmd)
+		if err != nil {
+			return err
+		}
+
+		client := cmd.Metadata["client"].(*wiki.Client)
+		page, err := client.GetContext(ctx, cmd.Int64(wikiID.Name))
+		if err != nil {
+			return err
+		}
+
+		ch, err := plan(page)
+		if err != nil {
+			return err
+		}
+		if err := client.ApplyContext(ctx, ch); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprint(cmd.Writer, ch.Report(client.DryRun))
+
+		logger.Info("stopped")
+		return nil
+	}
+
+	// openJournal sets the journal of the client. The file given by the journal flag is opened at once so that
+	// a wrong path fails before any page is changed, while the default file is created only when the first
+	// page is changed so that a run that changes nothing leaves no file behind.
+	openJournal := func(cmd *cli.Command, client *wiki.Client) (io.Closer, error) {
+		f := &journalFile{path: cmd.String(journal.Name)}
+		if f.path == "" {
+			f.path = fmt.Sprintf("bkl-journal-%s.jsonl", time.Now().Format("20060102T150405"))
+		} else if err := f.open(); err != nil {
+			return nil, err
+		}
+		client.Journal = wiki.NewJournal(f)
+		logger.Info("journal", "path", f.path)
+		return f, nil
+	}
+
+	summarize := func(cmd *cli.Command, changed, total int) {
+		if cmd.Bool(dryRun.Name) {
+			_, _ = fmt.Fprintf(cmd.Writer, "dry run: %d of %d pages would be changed\n", changed, total)
+		}
+	}
+
+	writeReport := func(cmd *cli.Command, report *wiki.RunReport) error {
+		path := cmd.String(reportFile.Name)
+		if path == "" {
+			return nil
+		}
+		f, err := os.OpenFile(filepath.Clean(path), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+		if err != nil {
+			return err
+		}
+		if err := report.Encode(f); err != nil {
+			_ = f.Close()
+			return err
+		}
+		return f.Close()
+	}
+
+	runBulk := func(ctx context.Context, cmd *cli.Command, items []*bulkItem) error {
+		// Without continue-on-error, items that are not started yet are skipped after the first failure,
+		// while the items in flight are allowed to finish.
+		var stopped atomic.Bool
+		run := func(ctx context.Context, item *bulkItem) (*bulkResult, error) {
+			if stopped.Load() {
+				return nil, errSkipped
+			}
+			res, err := item.run(ctx)
+			if err != nil && ctx.Err() != nil {
+				return nil, fmt.Errorf("%w: %w", errInterrupted, err)
+			}
+			if err != nil && !cmd.Bool(continueOnError.Name) {
+				stopped.Store(true)
+			}
+			return res, err
+		}
+
+		report := wiki.NewRunReport()
+		changed := 0
+		var errs []error
+		i := 0
+		for res, err := range backlog.Parallel(ctx, items, cmd.Int(concurrency.Name), run) {
+			item := items[i]
+			i++
+			switch {
+			case errors.Is(err, errInterrupted):
+				logger.Error("interrupted", "error", err)
+				report.Interrupt(item.id, item.name, err)
+				errs = append(errs, err)
+			case errors.Is(err, errSkipped), err != nil && ctx.Err() != nil && errors.Is(err, ctx.Err()):
+				// The context error without errInterrupted is yielded only for the items that were not started.
+				report.Skip(item.id, item.name)
+			case err != nil:
+				logger.Error("failed", "error", err)
+				report.Fail(item.id, item.name, err)
+				errs = append(errs, err)
+			default:
+				report.Succeed(item.id, item.name)
+				if res.changed {
+					changed++
+				}
+				_, _ = fmt.Fprint(cmd.Writer, res.report)
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+		}
+
+		summarize(cmd, changed, len(items))
+		if err := writeReport(cmd, report); err != nil {
+			errs = append(errs, err)
+		}
+		return errors.Join(errs...)
+	}
+
+	// pageItems returns the items of a bulk command that applies the changes planned by fn to the pages.
+	// As in tag-all, pages that the change leaves as they are succeed without a request.
+	pageItems := func(client *wiki.Client, pages []*wiki.Page, fn func(context.Context, *wiki.Page) (*wiki.Change, error)) []*bulkItem {
+		items := make([]*bulkItem, len(pages))
+		for i, page := range pages {
+			items[i] = &bulkItem{
+				id:   page.ID,
+				name: page.Name,
+				run: func(ctx context.Context) (*bulkResult, error) {
+					ch, err := fn(ctx, page)
+					if err != nil {
+						return nil, err
+					}
+					if !ch.Changed() {
+						return &bulkResult{report: fmt.Sprintf("unchanged: %d: %s\n", ch.Page.ID, ch.Page.Name)}, nil
+					}
+					if err := client.ApplyContext(ctx, ch); err != nil {
+						return nil, &wiki.PageError{PageID: page.ID, Err: err}
+					}
+					return &bulkResult{changed: true, report: ch.Report(client.DryRun)}, nil
+				},
+			}
+		}
+		return items
+	}
+
+	renameWikiAll := func(ctx context.Context, cmd *cli.Command) error {
+		logger.Info("started")
+
+		client := cmd.Metadata["client"].(*wiki.Client)
+		pages, err := client.ListContext(ctx, cmd.String(projectKey.Name), cmd.String(pattern.Name))
+		if err != nil {
+			return err
+		}
+
+		if !client.DryRun {
+			f, err := openJournal(cmd, client)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = f.Close() }()
+		}
+
+		rename := func(ctx context.Context, page *wiki.Page) (*wiki.Change, error) {
+			ch, err := wiki.PlanRename(page, cmd.String(oldString.Name), cmd.String(newString.Name))
+			if err != nil {
+				return nil, &wiki.PageError{PageID: page.ID, Err: err}
+			}
+			return ch, nil
+		}
+
+		if err := runBulk(ctx, cmd, pageItems(client, pages, rename)); err != nil {
+			return err
+		}
+
+		logger.Info("stopped")
+		return nil
+	}
+
+	replaceWikiAll := func(ctx context.Context, cmd *cli.Command) error {
+		logger.Info("started")
+
+		plan, err := planReplace(cmd)
+		if err != nil {
+			return err
+		}
+
+		client := cmd.Metadata["client"].(*wiki.Client)
+		pages, err := client.ListContext(ctx, cmd.String(projectKey.Name), cmd.String(pattern.Name))
+		if err != nil {
+			return err
+		}
+
+		if !client.DryRun {
+			f, err := openJournal(cmd, client)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = f.Close() }()
+		}
+
+		replace := func(ctx context.Context, page *wiki.Page) (*wiki.Change, error) {
+			detail, err := client.GetContext(ctx, page.ID)
+			if err != nil {
+				return nil, &wiki.PageError{PageID: page.ID, Err: err}
+			}
+			ch, err := plan(detail)
+			if err != nil {
+				return nil, &wiki.PageError{PageID: page.ID, Err: err}
+			}
+			return ch, nil
+		}
+
+		if err := runBulk(ctx, cmd, pageItems(client, pages, replace)); err != nil {
+			return err
+		}
+
+		logger.Info("stopped")
+		return nil
+	}
+
+	listWikiTags := func(ctx context.Context, cmd *cli.Command) error {
+		logger.Info("started")
+
+		client := cmd.Metadata["client"].(*wiki.Client)
+		tags, err := client.TagsContext(ctx, cmd.String(projectKey.Name))
+		if err != nil {
+			return err
+		}
+
+		enc := json.NewEncoder(cmd.Writer)
+		for _, tag := range tags {
+			if err := enc.Encode(tag); err != nil {
+				return err
+			}
+		}
+
+		logger.Info("stopped")
+		return nil
+	}
+
+	tagWikiAll := func(ctx context.Context, cmd *cli.Command) error {
+		logger.Info("started")
+
+		if len(cmd.StringSlice(addTags.Name)) == 0 && len(cmd.StringSlice(removeTags.Name)) == 0 {
+			return errors.New("no tags to add or remove: set add or remove")
+		}
+
+		client := cmd.Metadata["client"].(*wiki.Client)
+		pages, err := client.ListContext(ctx, cmd.String(projectKey.Name), cmd.String(pattern.Name), cmd.StringSlice(tagFilter.Name)...)
+		if err != nil {
+			return err
+		}
+
+		// The changes are planned first so that invalid tags are rejected before any page is changed.
+		items := make([]*bulkItem, len(pages))
+		for i, page := range pages {
+			ch, err := wiki.PlanTags(page, cmd.StringSlice(addTags.Name), cmd.StringSlice(removeTags.Name))
+			if err != nil {
+				return err
+			}
+			items[i] = &bulkItem{
+				id:   page.ID,
+				name: page.Name,
+				run: func(ctx context.Context) (*bulkResult, error) {
+					// Tags are changed by renaming, so pages that already have the tags succeed without a request.
+					if !ch.Changed() {
+						return &bulkResult{report: fmt.Sprintf("unchanged: %d: %s\n", page.ID, page.Name)}, nil
+					}
+					if err := client.ApplyContext(ctx, ch); err != nil {
+						return nil, &wiki.PageError{PageID: page.ID, Err: err}
+					}
+					return &bulkResult{changed: true, report: ch.Report(client.DryRun)}, nil
+				},
+			}
+		}
+
+		if !client.DryRun {
+			f, err := openJournal(cmd, client)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = f.Close() }()
+		}
+
+		if err := runBulk(ctx, cmd, items); err != nil {
+			return err
+		}
+
+		logger.Info("stopped")
+		return nil
+	}
+
+	rollbackWiki := func(ctx context.Context, cmd *cli.Command) error {
+		logger.Info("started")
+
+		f, err := os.Open(filepath.Clean(cmd.String(rollbackJournal.Name)))
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+
+		entries, err := wiki.ReadJournal(f)
+		if err != nil {
+			return err
+		}
+
+		client := cmd.Metadata["client"].(*wiki.Client)
+		if err := client.RollbackContext(ctx, entries); err != nil {
+			return err
+		}
+
+		summarize(cmd, len(entries), len(entries))
+		logger.Info("stopped")
+		return nil
+	}
+
+	exportWiki := func(ctx context.Context, cmd *cli.Command) error {
+		logger.Info("started")
+
+		client := cmd.Metadata["client"].(*wiki.Client)
+		pages, err := client.ListContext(ctx, cmd.String(projectKey.Name), cmd.String(pattern.Name))
+		if err != nil {
+			return err
+		}
+
+		// Names that differ only in the escaped segments would overwrite each other, so nothing is written for them.
+		paths := make(map[string]int64, len(pages))
+		for _, page := range pages {
+			path := wiki.ExportPath(page.Name)
+			if id, ok := paths[path]; ok {
+				return fmt.Errorf("wiki pages %d and %d are exported to the same file: %s", id, page.ID, path)
+			}
+			paths[path] = page.ID
+		}
+
+		dir := cmd.String(outDir.Name)
+		export := func(ctx context.Context, page *wiki.Page) (string, error) {
+			path, err := client.ExportContext(ctx, page, dir)
+			if err != nil {
+				return "", &wiki.PageError{PageID: page.ID, Err: err}
+			}
+			return path, nil
+		}
+		for path, err := range backlog.Parallel(ctx, pages, cmd.Int(concurrency.Name), export) {
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintln(cmd.Writer, path); err != nil {
+				return err
+			}
+		}
+
+		logger.Info("exported", "pages", len(pages), "dir", dir)
+		logger.Info("stopped")
+		return nil
+	}
+
+	pushWiki := func(ctx context.Context, cmd *cli.Command) error {
+		logger.Info("started")
+
+		if cmd.Args().Len() != 1 {
+			return errors.New("invalid arguments: expected DIR")
+		}
+		dir := cmd.Args().First()
+		local, err := wiki.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+		// An empty directory is more likely a wrong path than a request to delete every page.
+		if len(local) == 0 {
+			return fmt.Errorf("no markdown files in %s", dir)
+		}
+		if s := cmd.String(pattern.Name); s != "" {
+			r, err := regexp.Compile(s)
+			if err != nil {
+				return err
+			}
+			local = slices.DeleteFunc(local, func(page *wiki.Page) bool {
+				return !r.MatchString(page.Name)
+			})
+		}
+
+		client := cmd.Metadata["client"].(*wiki.Client)
+		p, err := (&project.Client{Client: client.Client}).GetContext(ctx, cmd.String(projectKey.Name))
+		if err != nil {
+			return err
+		}
+		remote, err := client.ListContext(ctx, cmd.String(projectKey.Name), cmd.String(pattern.Name))
+		if err != nil {
+			return err
+		}
+		ops, err := wiki.PlanPush(local, remote, cmd.Bool(prune.Name))
+		if err != nil {
+			return err
+		}
+
+		items := make([]*bulkItem, len(ops))
+		for i, op := range ops {
+			item := &bulkItem{}
+			if op.Remote != nil {
+				item.id, item.name = op.Remote.ID, op.Remote.Name
+			}
+			if op.Local != nil {
+				item.name = op.Local.Name
+			}
+			item.run = func(ctx context.Context) (*bulkResult, error) {
+				res, err := client.PushContext(ctx, p.ID, op)
+				if err != nil {
+					return nil, fmt.Errorf("failed to %s %s: %w", op.Action, item.name, err)
+				}
+				return &bulkResult{changed: res.Changed, report: res.Report}, nil
+			}
+			items[i] = item
+		}
+
+		if err := runBulk(ctx, cmd, items); err != nil {
+			return err
+		}
+
+		logger.Info("stopped")
+		return nil
+	}
+
+	historyWiki := func(ctx context.Context, cmd *cli.Command) error {
+		logger.Info("started")
+
+		var opts wiki.HistoryOptions
+		switch cmd.String(historyOrder.Name) {
+		case "asc":
+			opts.Ascending = true
+		case "desc":
+		default:
+			return fmt.Errorf("invalid order: %s", cmd.String(historyOrder.Name))
+		}
+
+		client := cmd.Metadata["client"].(*wiki.Client)
+		enc := json.NewEncoder(cmd.Writer)
+		for r, err := range client.HistoryAll(ctx, cmd.Int64(wikiID.Name), &opts, cmd.Int(historyMaxItems.Name)) {
+			if err != nil {
+				return err
+			}
+			// The content of every revision would flood the output, so it is left to diff.
+			r.Content = ""
+			if err := enc.Encode(r); err != nil {
+				return err
+			}
+		}
+
+		logger.Info("stopped")
+		return nil
+	}
+
+	restoreWiki := func(ctx context.Context, cmd *cli.Command) error {
+		logger.Info("started")
+
+		client := cmd.Metadata["client"].(*wiki.Client)
+		opts := &wiki.RestoreOptions{
+			ExpectedVersion: cmd.Int64(expectedVersion.Name),
+			MailNotify:      cmd.Bool(mailNotify.Name),
+		}
+		ch, err := client.RestoreContext(ctx, cmd.Int64(wikiID.Name), cmd.Int64(restoreVersion.Name), opts)
+		if err != nil {
+			return err
+		}
+		if ch.Changed() {
+			_, _ = fmt.Fprint(cmd.Writer, ch.Report(client.DryRun))
+		} else {
+			_, _ = fmt.Fprintf(cmd.Writer, "unchanged: %d: %s\n", ch.Page.ID, ch.Page.Name)
+		}
+
+		logger.Info("stopped")
+		return nil
+	}
+
+	diffWiki := func(ctx context.Context, cmd *cli.Command) error {
+		logger.Info("started")
+
+		client := cmd.Metadata["client"].(*wiki.Client)
+		id := cmd.Int64(wikiID.Name)
+		from, err := client.RevisionContext(ctx, id, cmd.Int64(fromVersion.Name))
+		if err != nil {
+			return err
+		}
+		var to *wiki.Revision
+		if cmd.IsSet(toVersion.Name) {
+			to, err = client.RevisionContext(ctx, id, cmd.Int64(toVersion.Name))
+			if err != nil {
+				return err
+			}
+		} else {
+			page, err := client.GetContext(ctx, id)
+			if err != nil {
+				return err
+			}
+			to = &wiki.Revision{PageID: page.ID, Name: page.Name, Content: page.Content}
+		}
+
+		if d := wiki.DiffRevisions(from, to); d != "" {
+			_, _ = fmt.Fprint(cmd.Writer, d)
+		} else {
+			logger.Info("no differences", "from", from.Label(), "to", to.Label())
+		}
+
+		logger.Info("stopped")
+		return nil
+	}
+
+	listAttachments := func(ctx context.Context, cmd *cli.Command) error {
+		logger.Info("started")
+
+		client := cmd.Metadata["client"].(*wiki.Client)
+		attachments, err := client.AttachmentsContext(ctx, cmd.Int64(wikiID.Name))
+		if err != nil {
+			return err
+		}
+
+		enc := json.NewEncoder(cmd.Writer)
+		for _, a := range attachments {
+			if err := enc.Encode(a); err != nil {
+				return err
+			}
+		}
+
+		logger.Info("stopped")
+		return nil
+	}
+
+	// selectAttachments returns the attachments of the page with the specified IDs, or all of them if no ID is specified.
+	selectAttachments := func(ctx context.Context, client *wiki.Client, id int64, ids []int64) ([]*backlog.Attachment, error) {
+		attachments, err := client.AttachmentsContext(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) == 0 {
+			return attachments, nil
+		}
+		selected := make([]*backlog.Attachment, 0, len(ids))
+		for _, attachmentID := range ids {
+			i := slices.IndexFunc(attachments, func(a *backlog.Attachment) bool {
+				return a.ID == attachmentID
+			})
+			if i < 0 {
+				return nil, fmt.Errorf("attachment %d not found in wiki page %d", attachmentID, id)
+			}
+			selected = append(selected, attachments[i])
+		}
+		return selected, nil
+	}
+
+	downloadAttachments := func(ctx context.Context, cmd *cli.Command) error {
+		logger.Info("started")
+
+		client := cmd.Metadata["client"].(*wiki.Client)
+		id := cmd.Int64(wikiID.Name)
+		attachments, err := selectAttachments(ctx, client, id, cmd.Int64Slice(attachmentIDs.Name))
+		if err != nil {
+			return err
+		}
+
+		// As in export, attachments that would overwrite each other are not downloaded at all.
+		paths := make(map[string]int64, len(attachments))
+		for _, a := range attachments {
+			path := wiki.AttachmentPath(a.Name)
+			if other, ok := paths[path]; ok {
+				return fmt.Errorf("attachments %d and %d are downloaded to the same file: %s", other, a.ID, path)
+			}
+			paths[path] = a.ID
+		}
+
+		// Existing files are checked first as well so that a download does not stop halfway through.
+		dir := cmd.String(attachmentDir.Name)
+		if !cmd.Bool(overwrite.Name) {
+			for path := range paths {
+				if _, err := os.Lstat(filepath.Join(dir, path)); err == nil {
+					return fmt.Errorf("file already exists: %s: use --%s to replace it", filepath.Join(dir, path), overwrite.Name)
+				}
+			}
+		}
+		for i, a := range attachments {
+			logger.Info("downloading", "file", a.Name, "size", a.Size, "progress", fmt.Sprintf("%d/%d", i+1, len(attachments)))
+			p := newProgress(a.Name, a.Size)
+			path, err := client.SaveAttachmentContext(ctx, id, a, dir, cmd.Bool(overwrite.Name), p.writer)
+			if err != nil {
+				return err
+			}
+			logger.Info("downloaded", "file", a.Name, "bytes", p.n)
+			if _, err := fmt.Fprintln(cmd.Writer, path); err != nil {
+				return err
+			}
+		}
+
+		logger.Info("stopped")
+		return nil
+	}
+
+	uploadAttachments := func(ctx context.Context, cmd *cli.Command) error {
+		logger.Info("started")
+
+		if cmd.Args().Len() == 0 {
+			return errors.New("invalid arguments: expected FILE")
+		}
+		// Every file is checked first so that a wrong path does not leave the page with only some of the files.
+		files := cmd.Args().Slice()
+		sizes := make([]int64, len(files))
+		for i, file := range files {
+			fi, err := os.Stat(file)
+			if err != nil {
+				return err
+			}
+			if fi.IsDir() {
+				return fmt.Errorf("%s is a directory", file)
+			}
+			sizes[i] = fi.Size()
+		}
+
+		client := cmd.Metadata["client"].(*wiki.Client)
+		id := cmd.Int64(wikiID.Name)
+		for i, file := range files {
+			name := filepath.Base(file)
+			logger.Info("uploading", "file", name, "size", sizes[i], "progress", fmt.Sprintf("%d/%d", i+1, len(files)))
+			p := newProgress(name, sizes[i])
+			open := func() (io.ReadCloser, error) {
+				f, err := os.Open(filepath.Clean(file))
+				if err != nil {
+					return nil, err
+				}
+				p.n = 0
+				return p.reader(f), nil
+			}
+			attachments, err := client.UploadAttachmentContext(ctx, id, name, open)
+			if err != nil {
+				return err
+			}
+			if client.DryRun {
+				_, _ = fmt.Fprintf(cmd.Writer, "would upload: %s\n", name)
+				continue
+			}
+			logger.Info("uploaded", "file", name, "bytes", p.n)
+			for _, a := range attachments {
+				_, _ = fmt.Fprintf(cmd.Writer, "uploaded: %d: %s\n", a.ID, a.Name)
+			}
+		}
+
+		logger.Info("stopped")
+		return nil
+	}
+
+	deleteAttachments := func(ctx context.Context, cmd *cli.Command) error {
+		logger.Info("started")
+
+		client := cmd.Metadata["client"].(*wiki.Client)
+		id := cmd.Int64(wikiID.Name)
+		attachments, err := selectAttachments(ctx, client, id, cmd.Int64Slice(deleteAttachmentIDs.Name))
+		if err != nil {
+			return err
+		}
+
+		for _, a := range attachments {
+			if _, err := client.DeleteAttachmentContext(ctx, id, a.ID); err != nil {
+				return err
+			}
+			if client.DryRun {
+				_, _ = fmt.Fprintf(cmd.Writer, "would delete: %d: %s\n", a.ID, a.Name)
+			} else {
+				_, _ = fmt.Fprintf(cmd.Writer, "deleted: %d: %s\n", a.ID, a.Name)
+			}
+		}
+
+		logger.Info("stopped")
+		return nil
+	}
+
+	issueListOptions := func(ctx context.Context, cmd *cli.Command) (*issue.ListOptions, error) {
+		client := cmd.Metadata["client"].(*issue.Client)
+		projects := &project.Client{Client: client.Client}
+
+		projectIDs := make([]int64, 0, len(cmd.StringSlice(projectKeys.Name)))
+		for _, key := range cmd.StringSlice(projectKeys.Name) {
+			p, err := projects.GetContext(ctx, key)
+			if err != nil {
+				return nil, err
+			}
+			projectIDs = append(projectIDs, p.ID)
+		}
+
+		return &issue.ListOptions{
+			ProjectIDs:     projectIDs,
+			IssueTypeIDs:   cmd.Int64Slice(issueTypeIDs.Name),
+			StatusIDs:      cmd.Int64Slice(statusIDs.Name),
+			PriorityIDs:    cmd.Int64Slice(priorityIDs.Name),
+			AssigneeIDs:    cmd.Int64Slice(assigneeIDs.Name),
+			Keyword:        cmd.String(keyword.Name),
+			CreatedSince:   cmd.Timestamp(createdSince.Name),
+			CreatedUntil:   cmd.Timestamp(createdUntil.Name),
+			UpdatedSince:   cmd.Timestamp(updatedSince.Name),
+			UpdatedUntil:   cmd.Timestamp(updatedUntil.Name),
+			StartDateSince: cmd.Timestamp(startDateSince.Name),
+			StartDateUntil: cmd.Timestamp(startDateUntil.Name),
+			DueDateSince:   cmd.Timestamp(dueDateSince.Name),
+			DueDateUntil:   cmd.Timestamp(dueDateUntil.Name),
+			Sort:           cmd.String(sort.Name),
+			Order:          cmd.String(order.Name),
+			Offset:         cmd.Int(offset.Name),
+			Count:          cmd.Int(count.Name),
+		}, nil
+	}
+
+	listIssue := func(ctx context.Context, cmd *cli.Command) error {
+		logger.Info("started")
+
+		client := cmd.Metadata["client"].(*issue.Client)
+		opts, err := issueListOptions(ctx, cmd)
+		if err != nil {
+			return err
+		}
+
+		enc := json.NewEncoder(cmd.Writer)
+		for i, err := range client.ListAll(ctx, opts, cmd.Int(maxItems.Name)) {
+			if err != nil {
+				return err
+			}
+			if err := enc.Encode(i); err != nil {
+				return err
+			}
+		}
+
+		logger.Info("stopped")
+		return nil
+	}
+
+	countIssue := func(ctx context.Context, cmd *cli.Command) error {
+		logger.Info("started")
+
+		client := cmd.Metadata["client"].(*issue.Client)
+		opts, err := issueListOptions(ctx, cmd)
+		if err != nil {
+			return err
+		}
+		n, err := client.CountContext(ctx, opts)
+		if err != nil {
+			return err
+		}
+
+		if err := json.NewEncoder(cmd.Writer).Encode(map[string]int{"count": n}); err != nil {
+			return err
+		}
+
+		logger.Info("stopped")
+		return nil
+	}
+
+	getIssue := func(ctx context.Context, cmd *cli.Command) error {
+		logger.Info("started")
+
+		client := cmd.Metadata["client"].(*issue.Client)
+		i, err := client.GetContext(ctx, cmd.String(issueKey.Name))
+		if err != nil {
+			return err
+		}
+
+		if err := json.NewEncoder(cmd.Writer).Encode(i); err != nil {
+			return err
+		}
+
+		logger.Info("stopped")
+		return nil
+	}
+
+	createIssue := func(ctx context.Context, cmd *cli.Command) error {
+		logger.Info("started")
+
+		client := cmd.Metadata["client"].(*issue.Client)
+		p, err := (&project.Client{Client: client.Client}).GetContext(ctx, cmd.String(projectKey.Name))
+		if err != nil {
+			return err
+		}
+
+		created, err := client.CreateContext(ctx, &issue.CreateInput{
+			ProjectID:   p.ID,
+			Summary:     cmd.String(summary.Name),
+			IssueTypeID: cmd.Int64(issueTypeID.Name),
+			PriorityID:  cmd.Int64(priorityID.Name),
+			Description: cmd.String(description.Name),
+			AssigneeID:  cmd.Int64(assigneeID.Name),
+			StartDate:   cmd.Timestamp(startDate.Name),
+			DueDate:     cmd.Timestamp(dueDate.Name),
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := json.NewEncoder(cmd.Writer).Encode(created); err != nil {
+			return err
+		}
+
+		logger.Info("stopped")
+		return nil
+	}
+
+	updateIssue := func(ctx context.Context, cmd *cli.Command) error {
+		logger.Info("started")
+
+		in := &issue.UpdateInput{}
+		if cmd.IsSet(summary.Name) {
+			in.Summary = new(cmd.String(summary.Name))
+		}
+		if cmd.IsSet(description.Name) {
+			in.Description = new(cmd.String(description.Name))
+		}
+		if cmd.IsSet(issueTypeID.Name) {
+			in.IssueTypeID = new(cmd.Int64(issueTypeID.Name))
+		}
+		if cmd.IsSet(statusID.Name) {
+			in.StatusID = new(cmd.Int64(statusID.Name))
+		}
+		if cmd.IsSet(priorityID.Name) {
+			in.PriorityID = new(cmd.Int64(priorityID.Name))
+		}
+		if cmd.IsSet(assigneeID.Name) {
+			in.AssigneeID = new(cmd.Int64(assigneeID.Name))
+		}
+		if cmd.IsSet(startDate.Name) {
+			in.StartDate = new(cmd.Timestamp(startDate.Name))
+		}
+		if cmd.IsSet(dueDate.Name) {
+			in.DueDate = new(cmd.Timestamp(dueDate.Name))
+		}
+		if cmd.IsSet(comment.Name) {
+			in.Comment = new(cmd.String(comment.Name))
+		}
+
+		client := cmd.Metadata["client"].(*issue.Client)
+		updated, err := client.UpdateContext(ctx, cmd.String(issueKey.Name), in)
+		if err != nil {
+			return err
+		}
+
+		if err := json.NewEncoder(cmd.Writer).Encode(updated); err != nil {
+			return err
+		}
+
+		logger.Info("stopped")
+		return nil
+	}
+
+	deleteIssue := func(ctx context.Context, cmd *cli.Command) error {
+		logger.Info("started")
+
+		client := cmd.Metadata["client"].(*issue.Client)
+		deleted, err := client.DeleteContext(ctx, cmd.String(issueKey.Name))
+		if err != nil {
+			return err
+		}
+
+		if err := json.NewEncoder(cmd.Writer).Encode(deleted); err != nil {
+			return err
+		}
+
+		logger.Info("stopped")
+		return nil
+	}
+
+	login := func(ctx context.Context, cmd *cli.Command) error {
+		if err := applyProfile(cmd); err != nil {
+			return err
+		}
+		logger = log.NewLogger(cmd.Writer, cmd.String(loglevel.Name))
+		logger.Info("started")
+
+		config := oauthConfig(cmd)
+		if config.BaseURL == "" {
+			return errors.New("empty URL")
+		}
+		if config.ClientID == "" || config.ClientSecret == "" {
+			return errors.New("empty client credentials")
+		}
+		store, err := tokenStore(cmd)
+		if err != nil {
+			return err
+		}
+
+		tok, err := oauth.Login(ctx, config, func(authURL string) error {
+			_, err := fmt.Fprintf(cmd.Writer, "Open the following URL in your browser to authorize bkl:\n\n%s\n\n", authURL)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if err := store.Save(tok); err != nil {
+			return err
+		}
+
+		logger.Info("token saved", "path", store.Path)
+		logger.Info("stopped")
+		return nil
+	}
+
+	showRateLimit := func(ctx context.Context, cmd *cli.Command) error {
+		logger.Info("started")
+
+		client := cmd.Metadata["client"].(*backlog.Client)
+		status, err := client.GetRateLimitContext(ctx)
+		if err != nil {
+			return err
+		}
+
+		if err := json.NewEncoder(cmd.Writer).Encode(status); err != nil {
+			return err
+		}
+
+		logger.Info("stopped")
+		return nil
+	}
+
+	listConfig := func(_ context.Context, cmd *cli.Command) error {
+		c, _, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		for _, n := range c.Names() {
+			mark := " "
+			if n == c.DefaultProfile {
+				mark = "*"
+			}
+			if _, err := fmt.Fprintf(cmd.Writer, "%s %s\n", mark, n); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	showConfig := func(_ context.Context, cmd *cli.Command) error {
+		if cmd.Args().Len() > 1 {
+			return errors.New("too many arguments: expected [NAME]")
+		}
+		c, _, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		n := cmd.Args().First()
+		if n == "" {
+			n = cmd.String(profile.Name)
+		}
+		p, err := c.Profile(n)
+		if err != nil {
+			return err
+		}
+		if p == nil {
+			return errors.New("no default profile: specify a profile name")
+		}
+		return p.Redacted().Encode(cmd.Writer)
+	}
+
+	setConfig := func(_ context.Context, cmd *cli.Command) error {
+		if cmd.Args().Len() != 3 {
+			return errors.New("invalid arguments: expected NAME KEY VALUE")
+		}
+		c, path, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		if err := c.Set(cmd.Args().Get(0), cmd.Args().Get(1), cmd.Args().Get(2)); err != nil {
+			return err
+		}
+		return c.Save(path)
+	}
+
+	useConfig := func(_ context.Context, cmd *cli.Command) error {
+		if cmd.Args().Len() != 1 {
+			return errors.New("invalid arguments: expected NAME")
+		}
+		c, path, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		if err := c.Use(cmd.Args().First()); err != nil {
+			return err
+		}
+		return c.Save(path)
+	}
+
+	deleteConfig := func(_ context.Context, cmd *cli.Command) error {
+		if cmd.Args().Len() != 1 {
+			return errors.New("invalid arguments: expected NAME")
+		}
+		c, path, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		if err := c.Delete(cmd.Args().First()); err != nil {
+			return err
+		}
+		return c.Save(path)
+	}
+
+	issueFilterFlags := []cli.Flag{
+		loglevel, baseURL, apiKey, clientID, clientSecret, tokenFile, projectKeys, issueTypeIDs, statusIDs, priorityIDs, assigneeIDs, keyword,
+		createdSince, createdUntil, updatedSince, updatedUntil, startDateSince, startDateUntil, dueDateSince, dueDateUntil,
+	}
+
+	return &cli.Command{
 		Name:                  name,
 		Version:               version.Version(),
 		Usage:                 "Backlog utilities",
@@ -200,6 +1813,10 @@ func newCmd(w, ew io.Writer) *cli.Command {
 		EnableShellCompletion: true,
 		Writer:                w,
 		ErrWriter:             ew,
+		Flags: []cli.Flag{
+			profile, configFile, retryInitialInterval, retryMaxInterval, retryMaxAttempts, retryMaxJitter,
+			retryStatus, retryNetworkErrors, retryIdempotentOnly,
+		},
 		Commands: []*cli.Command{
 			{
 				Name:  "wiki",
@@ -210,38 +1827,350 @@ func newCmd(w, ew io.Writer) *cli.Command {
 						Usage:  "List wiki pages with optional pattern",
 						Before: beforeWiki,
 						Action: listWiki,
-						Flags:  []cli.Flag{loglevel, baseURL, apiKey, projectKey, pattern},
+						Flags:  []cli.Flag{loglevel, baseURL, apiKey, clientID, clientSecret, tokenFile, projectKey, pattern, tagFilter},
 					},
 					{
 						Name:   "rename",
 						Usage:  "Rename wiki page",
 						Before: beforeWiki,
 						Action: renameWiki,
-						Flags:  []cli.Flag{loglevel, baseURL, apiKey, wikiID, oldString, newString},
+						Flags:  []cli.Flag{loglevel, baseURL, apiKey, clientID, clientSecret, tokenFile, wikiID, oldString, newString, dryRun},
 					},
 					{
 						Name:   "replace",
 						Usage:  "Replace strings in the content of wiki page",
 						Before: beforeWiki,
 						Action: replaceWiki,
-						Flags:  []cli.Flag{loglevel, baseURL, apiKey, wikiID, pairs},
+						Flags:  []cli.Flag{loglevel, baseURL, apiKey, clientID, clientSecret, tokenFile, wikiID, pairs, regex, multiline, ignoreCase, dryRun},
+					},
+					{
+						Name:   "create",
+						Usage:  "Create wiki page with content from a flag, a file or stdin",
+						Before: beforeWiki,
+						Action: createWiki,
+						Flags:  []cli.Flag{loglevel, baseURL, apiKey, clientID, clientSecret, tokenFile, projectKey, pageName, content, contentFile, mailNotify, dryRun},
+					},
+					{
+						Name:   "update",
+						Usage:  "Update name and content of wiki page in one request",
+						Before: beforeWiki,
+						Action: updateWiki,
+						Flags:  []cli.Flag{loglevel, baseURL, apiKey, clientID, clientSecret, tokenFile, wikiID, newName, content, contentFile, mailNotify, dryRun},
+					},
+					{
+						Name:   "delete",
+						Usage:  "Delete wiki page",
+						Before: beforeWiki,
+						Action: deleteWiki,
+						Flags:  []cli.Flag{loglevel, baseURL, apiKey, clientID, clientSecret, tokenFile, wikiID, dryRun},
 					},
 					{
 						Name:   "rename-all",
 						Usage:  "List wiki pages and rename them with optional pattern",
 						Before: beforeWiki,
 						Action: renameWikiAll,
-						Flags:  []cli.Flag{loglevel, baseURL, apiKey, projectKey, pattern, oldString, newString},
+						Flags:  []cli.Flag{loglevel, baseURL, apiKey, clientID, clientSecret, tokenFile, projectKey, pattern, oldString, newString, journal, concurrency, continueOnError, reportFile, dryRun},
 					},
 					{
 						Name:   "replace-all",
 						Usage:  "List wiki pages and replace strings in the content with optional pattern",
 						Before: beforeWiki,
 						Action: replaceWikiAll,
-						Flags:  []cli.Flag{loglevel, baseURL, apiKey, projectKey, pattern, pairs},
+						Flags:  []cli.Flag{loglevel, baseURL, apiKey, clientID, clientSecret, tokenFile, projectKey, pattern, pairs, regex, multiline, ignoreCase, journal, concurrency, continueOnError, reportFile, dryRun},
+					},
+					{
+						Name:   "tags",
+						Usage:  "List tags of wiki pages in the project",
+						Before: beforeWiki,
+						Action: listWikiTags,
+						Flags:  []cli.Flag{loglevel, baseURL, apiKey, clientID, clientSecret, tokenFile, projectKey},
+					},
+					{
+						Name:   "tag-all",
+						Usage:  "List wiki pages and add or remove tags with optional pattern",
+						Before: beforeWiki,
+						Action: tagWikiAll,
+						Flags:  []cli.Flag{loglevel, baseURL, apiKey, clientID, clientSecret, tokenFile, projectKey, pattern, tagFilter, addTags, removeTags, journal, concurrency, continueOnError, reportFile, dryRun},
+					},
+					{
+						Name:   "rollback",
+						Usage:  "Restore wiki pages from a journal written by rename-all, replace-all or tag-all",
+						Before: beforeWiki,
+						Action: rollbackWiki,
+						Flags:  []cli.Flag{loglevel, baseURL, apiKey, clientID, clientSecret, tokenFile, rollbackJournal, dryRun},
+					},
+					{
+						Name:   "export",
+						Usage:  "Export wiki pages to a directory tree of markdown files with front matter",
+						Before: beforeWiki,
+						Action: exportWiki,
+						Flags:  []cli.Flag{loglevel, baseURL, apiKey, clientID, clientSecret, tokenFile, projectKey, pattern, outDir, concurrency},
+					},
+					{
+						Name:      "push",
+						Usage:     "Create, update and optionally delete wiki pages to match a directory tree of markdown files",
+						ArgsUsage: "DIR",
+						Before:    beforeWiki,
+						Action:    pushWiki,
+						Flags:     []cli.Flag{loglevel, baseURL, apiKey, clientID, clientSecret, tokenFile, projectKey, pattern, prune, concurrency, continueOnError, reportFile, dryRun},
+					},
+					{
+						Name:   "history",
+						Usage:  "List revisions of wiki page with version, user and timestamp",
+						Before: beforeWiki,
+						Action: historyWiki,
+						Flags:  []cli.Flag{loglevel, baseURL, apiKey, clientID, clientSecret, tokenFile, wikiID, historyOrder, historyMaxItems},
+					},
+					{
+						Name:   "diff",
+						Usage:  "Print unified diff of wiki page between two revisions or a revision and the current content",
+						Before: beforeWiki,
+						Action: diffWiki,
+						Flags:  []cli.Flag{loglevel, baseURL, apiKey, clientID, clientSecret, tokenFile, wikiID, fromVersion, toVersion},
+					},
+					{
+						Name:   "restore",
+						Usage:  "Restore the content of wiki page to a previous revision",
+						Before: beforeWiki,
+						Action: restoreWiki,
+						Flags:  []cli.Flag{loglevel, baseURL, apiKey, clientID, clientSecret, tokenFile, wikiID, restoreVersion, expectedVersion, mailNotify, dryRun},
+					},
+					{
+						Name:  "attachments",
+						Usage: "List, download, upload and delete files attached to wiki page",
+						Commands: []*cli.Command{
+							{
+								Name:   "list",
+								Usage:  "List files attached to wiki page",
+								Before: beforeWiki,
+								Action: listAttachments,
+								Flags:  []cli.Flag{loglevel, baseURL, apiKey, clientID, clientSecret, tokenFile, wikiID},
+							},
+							{
+								Name:   "download",
+								Usage:  "Download files attached to wiki page into a directory",
+								Before: beforeWiki,
+								Action: downloadAttachments,
+								Flags:  []cli.Flag{loglevel, baseURL, apiKey, clientID, clientSecret, tokenFile, wikiID, attachmentIDs, attachmentDir, overwrite},
+							},
+							{
+								Name:      "upload",
+								Usage:     "Upload files and attach them to wiki page",
+								ArgsUsage: "FILE...",
+								Before:    beforeWiki,
+								Action:    uploadAttachments,
+								Flags:     []cli.Flag{loglevel, baseURL, apiKey, clientID, clientSecret, tokenFile, wikiID, dryRun},
+							},
+							{
+								Name:   "delete",
+								Usage:  "Delete files attached to wiki page",
+								Before: beforeWiki,
+								Action: deleteAttachments,
+								Flags:  []cli.Flag{loglevel, baseURL, apiKey, clientID, clientSecret, tokenFile, wikiID, deleteAttachmentIDs, dryRun},
+							},
+						},
+					},
+				},
+			},
+			{
+				Name:  "auth",
+				Usage: "Backlog authentication utilities",
+				Commands: []*cli.Command{
+					{
+						Name:   "login",
+						Usage:  "Authorize bkl with an OAuth 2.0 application and save the token",
+						Action: login,
+						Flags:  []cli.Flag{loglevel, baseURL, clientID, clientSecret, redirectURL, tokenFile},
+					},
+				},
+			},
+			{
+				Name:   "ratelimit",
+				Usage:  "Show rate limits of the space with remaining counts and reset times",
+				Before: beforeRateLimit,
+				Action: showRateLimit,
+				Flags:  []cli.Flag{loglevel, baseURL, apiKey, clientID, clientSecret, tokenFile},
+			},
+			{
+				Name:  "config",
+				Usage: "Manage profiles in the config file",
+				Commands: []*cli.Command{
+					{
+						Name:   "list",
+						Usage:  "List profiles, marking the default one with *",
+						Action: listConfig,
+					},
+					{
+						Name:      "show",
+						Usage:     "Show a profile with the credentials redacted",
+						ArgsUsage: "[NAME]",
+						Action:    showConfig,
+					},
+					{
+						Name:        "set",
+						Usage:       "Set a key of a profile, creating the profile if needed",
+						Description: "An empty VALUE clears the key. KEY is one of: " + strings.Join(config.Keys(), ", "),
+						ArgsUsage:   "NAME KEY VALUE",
+						Action:      setConfig,
+					},
+					{
+						Name:      "use",
+						Usage:     "Make a profile the default one",
+						ArgsUsage: "NAME",
+						Action:    useConfig,
+					},
+					{
+						Name:      "delete",
+						Usage:     "Delete a profile",
+						ArgsUsage: "NAME",
+						Action:    deleteConfig,
+					},
+				},
+			},
+			{
+				Name:  "issue",
+				Usage: "Backlog issue utilities",
+				Commands: []*cli.Command{
+					{
+						Name:   "list",
+						Usage:  "List issues with optional filters",
+						Before: beforeIssue,
+						Action: listIssue,
+						Flags:  append(issueFilterFlags, sort, order, offset, count, maxItems),
+					},
+					{
+						Name:   "count",
+						Usage:  "Count issues with optional filters",
+						Before: beforeIssue,
+						Action: countIssue,
+						Flags:  issueFilterFlags,
+					},
+					{
+						Name:   "get",
+						Usage:  "Get issue",
+						Before: beforeIssue,
+						Action: getIssue,
+						Flags:  []cli.Flag{loglevel, baseURL, apiKey, clientID, clientSecret, tokenFile, issueKey},
+					},
+					{
+						Name:   "create",
+						Usage:  "Create issue",
+						Before: beforeIssue,
+						Action: createIssue,
+						Flags:  []cli.Flag{loglevel, baseURL, apiKey, clientID, clientSecret, tokenFile, projectKey, summary, issueTypeID, priorityID, description, assigneeID, startDate, dueDate},
+					},
+					{
+						Name:   "update",
+						Usage:  "Update issue",
+						Before: beforeIssue,
+						Action: updateIssue,
+						Flags:  []cli.Flag{loglevel, baseURL, apiKey, clientID, clientSecret, tokenFile, issueKey, summary, description, issueTypeID, statusID, priorityID, assigneeID, startDate, dueDate, comment},
+					},
+					{
+						Name:   "delete",
+						Usage:  "Delete issue",
+						Before: beforeIssue,
+						Action: deleteIssue,
+						Flags:  []cli.Flag{loglevel, baseURL, apiKey, clientID, clientSecret, tokenFile, issueKey},
 					},
 				},
 			},
 		},
 	}
 }
+
+// progress logs the bytes transferred for a file at most once a second, so that long transfers show they are alive.
+type progress struct {
+	name string
+	size int64
+	n    int64
+	last time.Time
+}
+
+func newProgress(name string, size int64) *progress {
+	return &progress{name: name, size: size, last: time.Now()}
+}
+
+func (p *progress) add(n int) {
+	p.n += int64(n)
+	if now := time.Now(); now.Sub(p.last) >= time.Second {
+		p.last = now
+		logger.Info("progress", "file", p.name, "bytes", p.n, "size", p.size)
+	}
+}
+
+func (p *progress) writer(w io.Writer) io.Writer {
+	return &progressWriter{w: w, p: p}
+}
+
+func (p *progress) reader(r io.ReadCloser) io.ReadCloser {
+	return &progressReader{ReadCloser: r, p: p}
+}
+
+type progressWriter struct {
+	w io.Writer
+	p *progress
+}
+
+func (w *progressWriter) Write(b []byte) (int, error) {
+	n, err := w.w.Write(b)
+	w.p.add(n)
+	return n, err
+}
+
+type progressReader struct {
+	io.ReadCloser
+	p *progress
+}
+
+func (r *progressReader) Read(b []byte) (int, error) {
+	n, err := r.ReadCloser.Read(b)
+	r.p.add(n)
+	return n, err
+}
+
+// bulkItem represents an item processed by a bulk command, such as a wiki page to change or an operation of a push.
+// The ID is 0 for an item that has no wiki page yet.
+type bulkItem struct {
+	id   int64
+	name string
+	run  func(context.Context) (*bulkResult, error)
+}
+
+// bulkResult represents the outcome of a bulk item that succeeded.
+type bulkResult struct {
+	changed bool
+	report  string
+}
+
+// journalFile is the file of a journal, which is opened by the first write unless it is opened beforehand.
+// The journal serializes the writes, so it is not locked.
+type journalFile struct {
+	path string
+	f    *os.File
+}
+
+func (j *journalFile) open() error {
+	f, err := os.OpenFile(filepath.Clean(j.path), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	j.f = f
+	return nil
+}
+
+func (j *journalFile) Write(p []byte) (int, error) {
+	if j.f == nil {
+		if err := j.open(); err != nil {
+			return 0, err
+		}
+	}
+	return j.f.Write(p)
+}
+
+func (j *journalFile) Close() error {
+	if j.f == nil {
+		return nil
+	}
+	return j.f.Close()
+}
@@ -2,8 +2,20 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/nekrassov01/backlog-utils/backlog/wiki"
+	"github.com/nekrassov01/backlog-utils/config"
+	"github.com/stretchr/testify/assert"
 )
 
 func Test_cli(t *testing.T) {
@@ -92,6 +104,191 @@ func Test_cli(t *testing.T) {
 			args:    []string{name, "wiki", "replace-all", "--base-url", "test", "--api-key", "test", "--project-key", "test", "--pattern", "", "--pairs", "key"},
 			wantErr: true,
 		},
+		{
+			name:    "replace invalid regex",
+			args:    []string{name, "wiki", "replace", "--base-url", "test", "--api-key", "test", "--wiki-id", "1", "--regex", "--pairs", "[", "--pairs", "value"},
+			wantErr: true,
+		},
+		{
+			name:    "replace-all invalid regex",
+			args:    []string{name, "wiki", "replace-all", "--base-url", "test", "--api-key", "test", "--project-key", "test", "--regex", "--pairs", "(", "--pairs", "value"},
+			wantErr: true,
+		},
+		{
+			name:    "replace-all regex invalid pairs",
+			args:    []string{name, "wiki", "replace-all", "--base-url", "test", "--api-key", "test", "--project-key", "test", "--regex", "--pairs", "key"},
+			wantErr: true,
+		},
+		{
+			name:    "rollback empty journal",
+			args:    []string{name, "wiki", "rollback", "--base-url", "test", "--api-key", "test", "--journal", ""},
+			wantErr: true,
+		},
+		{
+			name:    "rollback missing journal",
+			args:    []string{name, "wiki", "rollback", "--base-url", "test", "--api-key", "test", "--journal", "testdata/missing.jsonl"},
+			wantErr: true,
+		},
+		{
+			name:    "create empty name",
+			args:    []string{name, "wiki", "create", "--base-url", "test", "--api-key", "test", "--project-key", "test", "--name", ""},
+			wantErr: true,
+		},
+		{
+			name:    "update no fields",
+			args:    []string{name, "wiki", "update", "--base-url", "test", "--api-key", "test", "--wiki-id", "1"},
+			wantErr: true,
+		},
+		{
+			name:    "update content and content file",
+			args:    []string{name, "wiki", "update", "--base-url", "test", "--api-key", "test", "--wiki-id", "1", "--content", "a", "--content-file", "-"},
+			wantErr: true,
+		},
+		{
+			name:    "update missing content file",
+			args:    []string{name, "wiki", "update", "--base-url", "test", "--api-key", "test", "--wiki-id", "1", "--content-file", "testdata/missing.md"},
+			wantErr: true,
+		},
+		{
+			name:    "delete empty wiki id",
+			args:    []string{name, "wiki", "delete", "--base-url", "test", "--api-key", "test"},
+			wantErr: true,
+		},
+		{
+			name:    "export empty out",
+			args:    []string{name, "wiki", "export", "--base-url", "test", "--api-key", "test", "--project-key", "test", "--out", ""},
+			wantErr: true,
+		},
+		{
+			name:    "push no directory",
+			args:    []string{name, "wiki", "push", "--base-url", "test", "--api-key", "test", "--project-key", "test"},
+			wantErr: true,
+		},
+		{
+			name:    "push empty directory",
+			args:    []string{name, "wiki", "push", "--base-url", "test", "--api-key", "test", "--project-key", "test", "testdata/missing"},
+			wantErr: true,
+		},
+		{
+			name:    "history empty wiki id",
+			args:    []string{name, "wiki", "history", "--base-url", "test", "--api-key", "test"},
+			wantErr: true,
+		},
+		{
+			name:    "history invalid order",
+			args:    []string{name, "wiki", "history", "--base-url", "test", "--api-key", "test", "--wiki-id", "1", "--order", "up"},
+			wantErr: true,
+		},
+		{
+			name:    "diff empty from",
+			args:    []string{name, "wiki", "diff", "--base-url", "test", "--api-key", "test", "--wiki-id", "1"},
+			wantErr: true,
+		},
+		{
+			name:    "restore empty version",
+			args:    []string{name, "wiki", "restore", "--base-url", "test", "--api-key", "test", "--wiki-id", "1"},
+			wantErr: true,
+		},
+		{
+			name:    "tags empty project key",
+			args:    []string{name, "wiki", "tags", "--base-url", "test", "--api-key", "test"},
+			wantErr: true,
+		},
+		{
+			name:    "tag-all no tags",
+			args:    []string{name, "wiki", "tag-all", "--base-url", "test", "--api-key", "test", "--project-key", "test"},
+			wantErr: true,
+		},
+		{
+			name:    "attachments upload no file",
+			args:    []string{name, "wiki", "attachments", "upload", "--base-url", "test", "--api-key", "test", "--wiki-id", "1"},
+			wantErr: true,
+		},
+		{
+			name:    "attachments upload missing file",
+			args:    []string{name, "wiki", "attachments", "upload", "--base-url", "test", "--api-key", "test", "--wiki-id", "1", "testdata/missing"},
+			wantErr: true,
+		},
+		{
+			name:    "attachments delete empty attachment id",
+			args:    []string{name, "wiki", "attachments", "delete", "--base-url", "test", "--api-key", "test", "--wiki-id", "1"},
+			wantErr: true,
+		},
+		{
+			name:    "auth login empty url",
+			args:    []string{name, "auth", "login", "--base-url", "", "--client-id", "id", "--client-secret", "secret"},
+			wantErr: true,
+		},
+		{
+			name:    "auth login empty client secret",
+			args:    []string{name, "auth", "login", "--base-url", "test", "--client-id", "id", "--client-secret", ""},
+			wantErr: true,
+		},
+		{
+			name:    "auth login non-loopback redirect url",
+			args:    []string{name, "auth", "login", "--base-url", "test", "--client-id", "id", "--client-secret", "secret", "--redirect-url", "http://example.com/callback"},
+			wantErr: true,
+		},
+		{
+			name:    "list oauth without token",
+			args:    []string{name, "wiki", "list", "--base-url", "test", "--client-id", "id", "--client-secret", "secret", "--token-file", "testdata/missing.json", "--project-key", "test"},
+			wantErr: true,
+		},
+		{
+			name:    "issue list empty url",
+			args:    []string{name, "issue", "list", "--base-url", "", "--api-key", "test"},
+			wantErr: true,
+		},
+		{
+			name:    "issue list invalid date",
+			args:    []string{name, "issue", "list", "--base-url", "test", "--api-key", "test", "--created-since", "2025/04/01"},
+			wantErr: true,
+		},
+		{
+			name:    "issue list invalid status id",
+			args:    []string{name, "issue", "list", "--base-url", "test", "--api-key", "test", "--status-id", "open"},
+			wantErr: true,
+		},
+		{
+			name:    "issue count empty api key",
+			args:    []string{name, "issue", "count", "--base-url", "test", "--api-key", ""},
+			wantErr: true,
+		},
+		{
+			name:    "issue get empty issue key",
+			args:    []string{name, "issue", "get", "--base-url", "test", "--api-key", "test", "--issue-key", ""},
+			wantErr: true,
+		},
+		{
+			name:    "issue create empty project key",
+			args:    []string{name, "issue", "create", "--base-url", "test", "--api-key", "test", "--project-key", "", "--summary", "test"},
+			wantErr: true,
+		},
+		{
+			name:    "issue update empty issue key",
+			args:    []string{name, "issue", "update", "--base-url", "test", "--api-key", "test", "--issue-key", "", "--summary", "test"},
+			wantErr: true,
+		},
+		{
+			name:    "issue update no fields",
+			args:    []string{name, "issue", "update", "--base-url", "test", "--api-key", "test", "--issue-key", "TEST-1"},
+			wantErr: true,
+		},
+		{
+			name:    "ratelimit empty url",
+			args:    []string{name, "ratelimit", "--base-url", "", "--api-key", "test"},
+			wantErr: true,
+		},
+		{
+			name:    "ratelimit empty api key",
+			args:    []string{name, "ratelimit", "--base-url", "test", "--api-key", ""},
+			wantErr: true,
+		},
+		{
+			name:    "issue delete empty issue key",
+			args:    []string{name, "issue", "delete", "--base-url", "test", "--api-key", "test", "--issue-key", ""},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -103,3 +300,1422 @@ func Test_cli(t *testing.T) {
 		})
 	}
 }
+
+func Test_cli_replace(t *testing.T) {
+	type expected struct {
+		body  string
+		calls int
+	}
+	tests := []struct {
+		name     string
+		args     []string
+		expected expected
+	}{
+		{
+			name: "basic",
+			args: []string{},
+			expected: expected{
+				body:  "content=new+text",
+				calls: 1,
+			},
+		},
+		{
+			name: "dry run",
+			args: []string{"--dry-run"},
+			expected: expected{
+				calls: 0,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			baseURL := "https://example.com"
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				baseURL+"/api/v2/wikis/7?apiKey=dummy",
+				httpmock.NewStringResponder(200, `{"id":7,"projectId":10,"name":"Home","content":"old text"}`),
+			)
+			var body string
+			httpmock.RegisterResponder(
+				http.MethodPatch,
+				baseURL+"/api/v2/wikis/7?apiKey=dummy",
+				func(req *http.Request) (*http.Response, error) {
+					b, err := io.ReadAll(req.Body)
+					if err != nil {
+						return nil, err
+					}
+					body = string(b)
+					return httpmock.NewStringResponse(200, `{}`), nil
+				},
+			)
+
+			// The page is looked up by its ID, not by listing the pages of a project.
+			args := []string{name, "wiki", "replace", "--base-url", baseURL, "--api-key", "dummy", "--wiki-id", "7", "--pairs", "old", "--pairs", "new"}
+			err := newCmd(io.Discard, io.Discard).Run(context.Background(), append(args, tt.args...))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.body, body)
+			info := httpmock.GetCallCountInfo()
+			assert.Equal(t, 1, info["GET "+baseURL+"/api/v2/wikis/7?apiKey=dummy"])
+			assert.Equal(t, tt.expected.calls, info["PATCH "+baseURL+"/api/v2/wikis/7?apiKey=dummy"])
+			assert.Equal(t, 1+tt.expected.calls, httpmock.GetTotalCallCount())
+		})
+	}
+}
+
+func Test_cli_bulkReport(t *testing.T) {
+	type expected struct {
+		report  *wiki.RunReport
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		args     []string
+		expected expected
+	}{
+		{
+			name: "stop at first failure",
+			args: []string{},
+			expected: expected{
+				report: &wiki.RunReport{
+					Succeeded: []*wiki.ReportEntry{{PageID: 1, Name: "a"}},
+					Failed:    []*wiki.ReportEntry{{PageID: 2, Name: "b"}},
+					Skipped:   []*wiki.ReportEntry{{PageID: 3, Name: "c"}},
+				},
+				isError: true,
+			},
+		},
+		{
+			name: "continue on error",
+			args: []string{"--continue-on-error"},
+			expected: expected{
+				report: &wiki.RunReport{
+					Succeeded: []*wiki.ReportEntry{{PageID: 1, Name: "a"}, {PageID: 3, Name: "c"}},
+					Failed:    []*wiki.ReportEntry{{PageID: 2, Name: "b"}},
+					Skipped:   []*wiki.ReportEntry{},
+				},
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			baseURL := "https://example.com"
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				baseURL+"/api/v2/wikis?apiKey=dummy&projectIdOrKey=TEST",
+				httpmock.NewStringResponder(200, `[{"id":1,"name":"a"},{"id":2,"name":"b"},{"id":3,"name":"c"}]`),
+			)
+			for _, id := range []int64{1, 3} {
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					fmt.Sprintf("%s/api/v2/wikis/%d?apiKey=dummy", baseURL, id),
+					httpmock.NewStringResponder(200, fmt.Sprintf(`{"id":%d,"name":"page","content":"old"}`, id)),
+				)
+				httpmock.RegisterResponder(
+					http.MethodPatch,
+					fmt.Sprintf("%s/api/v2/wikis/%d?apiKey=dummy", baseURL, id),
+					httpmock.NewStringResponder(200, `{}`),
+				)
+			}
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				baseURL+"/api/v2/wikis/2?apiKey=dummy",
+				httpmock.NewStringResponder(400, `{"errors":[]}`),
+			)
+
+			dir := t.TempDir()
+			report := filepath.Join(dir, "report.json")
+			args := []string{
+				name, "wiki", "replace-all", "--base-url", baseURL, "--api-key", "dummy", "--project-key", "TEST",
+				"--pairs", "old", "--pairs", "new", "--journal", filepath.Join(dir, "journal.jsonl"), "--report", report,
+			}
+			err := newCmd(io.Discard, io.Discard).Run(context.Background(), append(args, tt.args...))
+			if tt.expected.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			b, err := os.ReadFile(report)
+			assert.NoError(t, err)
+			actual := &wiki.RunReport{}
+			assert.NoError(t, json.Unmarshal(b, actual))
+			assert.Equal(t, tt.expected.report.Succeeded, actual.Succeeded)
+			assert.Equal(t, tt.expected.report.Skipped, actual.Skipped)
+			assert.Len(t, actual.Failed, len(tt.expected.report.Failed))
+			for i, f := range tt.expected.report.Failed {
+				assert.Equal(t, f.PageID, actual.Failed[i].PageID)
+				assert.Equal(t, f.Name, actual.Failed[i].Name)
+			}
+		})
+	}
+}
+
+func Test_cli_bulkCanceled(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	baseURL := "https://example.com"
+	httpmock.RegisterResponder(
+		http.MethodGet,
+		baseURL+"/api/v2/wikis?apiKey=dummy&projectIdOrKey=TEST",
+		httpmock.NewStringResponder(200, `[{"id":1,"name":"a"},{"id":2,"name":"b"},{"id":3,"name":"c"},{"id":4,"name":"d"}]`),
+	)
+	for id := 1; id <= 4; id++ {
+		httpmock.RegisterResponder(
+			http.MethodGet,
+			fmt.Sprintf("%s/api/v2/wikis/%d?apiKey=dummy", baseURL, id),
+			httpmock.NewStringResponder(200, fmt.Sprintf(`{"id":%d,"name":"page","content":"old"}`, id)),
+		)
+	}
+	// Page 1 is updated and page 2 is in flight when the command is canceled, and pages 3 and 4 are not started.
+	started := make(chan struct{})
+	httpmock.RegisterResponder(
+		http.MethodPatch,
+		baseURL+"/api/v2/wikis/1?apiKey=dummy",
+		func(req *http.Request) (*http.Response, error) {
+			<-started
+			cancel()
+			return httpmock.NewStringResponse(200, `{}`), nil
+		},
+	)
+	httpmock.RegisterResponder(
+		http.MethodPatch,
+		baseURL+"/api/v2/wikis/2?apiKey=dummy",
+		func(req *http.Request) (*http.Response, error) {
+			close(started)
+			<-req.Context().Done()
+			return nil, req.Context().Err()
+		},
+	)
+
+	dir := t.TempDir()
+	report := filepath.Join(dir, "report.json")
+	args := []string{
+		name, "wiki", "replace-all", "--base-url", baseURL, "--api-key", "dummy", "--project-key", "TEST",
+		"--pairs", "old", "--pairs", "new", "--concurrency", "2", "--continue-on-error",
+		"--journal", filepath.Join(dir, "journal.jsonl"), "--report", report,
+	}
+	err := newCmd(io.Discard, io.Discard).Run(ctx, args)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	b, err := os.ReadFile(report)
+	assert.NoError(t, err)
+	actual := &wiki.RunReport{}
+	assert.NoError(t, json.Unmarshal(b, actual))
+	assert.Equal(t, []*wiki.ReportEntry{{PageID: 1, Name: "a"}}, actual.Succeeded)
+	assert.Empty(t, actual.Failed)
+	assert.Len(t, actual.Interrupted, 1)
+	assert.Equal(t, int64(2), actual.Interrupted[0].PageID)
+	assert.Equal(t, []*wiki.ReportEntry{{PageID: 3, Name: "c"}, {PageID: 4, Name: "d"}}, actual.Skipped)
+	info := httpmock.GetCallCountInfo()
+	assert.Zero(t, info["PATCH "+baseURL+"/api/v2/wikis/3?apiKey=dummy"])
+	assert.Zero(t, info["PATCH "+baseURL+"/api/v2/wikis/4?apiKey=dummy"])
+
+	// Only the page that was updated is recorded, so that a rollback does not touch the others.
+	f, err := os.Open(filepath.Join(dir, "journal.jsonl"))
+	assert.NoError(t, err)
+	defer func() { _ = f.Close() }()
+	entries, err := wiki.ReadJournal(f)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, int64(1), entries[0].PageID)
+}
+
+func Test_cli_bulkUnchanged(t *testing.T) {
+	type expected struct {
+		patched []int64
+	}
+	tests := []struct {
+		name     string
+		args     []string
+		expected expected
+	}{
+		{
+			name: "rename-all",
+			args: []string{"rename-all", "--old", "old", "--new", "new"},
+			expected: expected{
+				patched: []int64{1},
+			},
+		},
+		{
+			name: "replace-all",
+			args: []string{"replace-all", "--pairs", "old", "--pairs", "new"},
+			expected: expected{
+				patched: []int64{1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			baseURL := "https://example.com"
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				baseURL+"/api/v2/wikis?apiKey=dummy&projectIdOrKey=TEST",
+				httpmock.NewStringResponder(200, `[{"id":1,"name":"old-a"},{"id":2,"name":"b"}]`),
+			)
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				baseURL+"/api/v2/wikis/1?apiKey=dummy",
+				httpmock.NewStringResponder(200, `{"id":1,"name":"old-a","content":"old"}`),
+			)
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				baseURL+"/api/v2/wikis/2?apiKey=dummy",
+				httpmock.NewStringResponder(200, `{"id":2,"name":"b","content":"b"}`),
+			)
+			for _, id := range []int64{1, 2} {
+				httpmock.RegisterResponder(
+					http.MethodPatch,
+					fmt.Sprintf("%s/api/v2/wikis/%d?apiKey=dummy", baseURL, id),
+					httpmock.NewStringResponder(200, `{}`),
+				)
+			}
+
+			dir := t.TempDir()
+			journal := filepath.Join(dir, "journal.jsonl")
+			report := filepath.Join(dir, "report.json")
+			args := []string{name, "wiki", tt.args[0], "--base-url", baseURL, "--api-key", "dummy", "--project-key", "TEST", "--journal", journal, "--report", report}
+			err := newCmd(io.Discard, io.Discard).Run(context.Background(), append(args, tt.args[1:]...))
+			assert.NoError(t, err)
+
+			// Pages that are left as they are succeed without a request and a journal entry.
+			b, err := os.ReadFile(report)
+			assert.NoError(t, err)
+			actual := &wiki.RunReport{}
+			assert.NoError(t, json.Unmarshal(b, actual))
+			assert.Equal(t, []*wiki.ReportEntry{{PageID: 1, Name: "old-a"}, {PageID: 2, Name: "b"}}, actual.Succeeded)
+			info := httpmock.GetCallCountInfo()
+			var patched []int64
+			for _, id := range []int64{1, 2} {
+				if info[fmt.Sprintf("PATCH %s/api/v2/wikis/%d?apiKey=dummy", baseURL, id)] > 0 {
+					patched = append(patched, id)
+				}
+			}
+			assert.Equal(t, tt.expected.patched, patched)
+			f, err := os.Open(journal)
+			assert.NoError(t, err)
+			defer func() { _ = f.Close() }()
+			entries, err := wiki.ReadJournal(f)
+			assert.NoError(t, err)
+			assert.Len(t, entries, len(tt.expected.patched))
+		})
+	}
+}
+
+func Test_cli_journal(t *testing.T) {
+	type expected struct {
+		files int
+	}
+	tests := []struct {
+		name     string
+		list     string
+		args     []string
+		expected expected
+	}{
+		{
+			name: "no pages",
+			list: `[]`,
+			expected: expected{
+				files: 0,
+			},
+		},
+		{
+			name: "no changes",
+			list: `[{"id":1,"name":"a"}]`,
+			expected: expected{
+				files: 0,
+			},
+		},
+		{
+			name: "changes",
+			list: `[{"id":1,"name":"old-a"}]`,
+			expected: expected{
+				files: 1,
+			},
+		},
+		{
+			name: "journal flag",
+			list: `[]`,
+			args: []string{"--journal", "journal.jsonl"},
+			expected: expected{
+				files: 1,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			baseURL := "https://example.com"
+			httpmock.RegisterResponder(http.MethodGet, baseURL+"/api/v2/wikis?apiKey=dummy&projectIdOrKey=TEST", httpmock.NewStringResponder(200, tt.list))
+			httpmock.RegisterResponder(http.MethodPatch, baseURL+"/api/v2/wikis/1?apiKey=dummy", httpmock.NewStringResponder(200, `{}`))
+
+			// The default journal is created in the current directory only when a page is changed.
+			dir := t.TempDir()
+			t.Chdir(dir)
+			args := []string{name, "wiki", "rename-all", "--base-url", baseURL, "--api-key", "dummy", "--project-key", "TEST", "--old", "old", "--new", "new"}
+			err := newCmd(io.Discard, io.Discard).Run(context.Background(), append(args, tt.args...))
+			assert.NoError(t, err)
+			entries, err := os.ReadDir(dir)
+			assert.NoError(t, err)
+			assert.Len(t, entries, tt.expected.files)
+		})
+	}
+}
+
+func Test_cli_wikiCRUD(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "content.md")
+	assert.NoError(t, os.WriteFile(file, []byte("from file"), 0o600))
+
+	type expected struct {
+		method string
+		body   string
+	}
+	tests := []struct {
+		name     string
+		args     []string
+		stdin    string
+		expected expected
+	}{
+		{
+			name:  "create from stdin",
+			args:  []string{"create", "--project-key", "TEST", "--name", "Design/API", "--content-file", "-", "--mail-notify"},
+			stdin: "from stdin",
+			expected: expected{
+				method: http.MethodPost,
+				body:   "content=from+stdin&mailNotify=true&name=Design%2FAPI&projectId=10",
+			},
+		},
+		{
+			name: "create without content",
+			args: []string{"create", "--project-key", "TEST", "--name", "Design/API"},
+			expected: expected{
+				method: http.MethodPost,
+				body:   "content=&mailNotify=false&name=Design%2FAPI&projectId=10",
+			},
+		},
+		{
+			name: "update name and content from file",
+			args: []string{"update", "--wiki-id", "1", "--name", "Design/API v2", "--content-file", file},
+			expected: expected{
+				method: http.MethodPatch,
+				body:   "content=from+file&name=Design%2FAPI+v2",
+			},
+		},
+		{
+			name: "update content",
+			args: []string{"update", "--wiki-id", "1", "--content", "inline", "--mail-notify"},
+			expected: expected{
+				method: http.MethodPatch,
+				body:   "content=inline&mailNotify=true",
+			},
+		},
+		{
+			name: "update dry run",
+			args: []string{"update", "--wiki-id", "1", "--content", "inline", "--dry-run"},
+		},
+		{
+			name: "delete",
+			args: []string{"delete", "--wiki-id", "1"},
+			expected: expected{
+				method: http.MethodDelete,
+			},
+		},
+		{
+			name: "delete dry run",
+			args: []string{"delete", "--wiki-id", "1", "--dry-run"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			baseURL := "https://example.com"
+			page := `{"id":1,"projectId":10,"name":"Design/API","content":"old"}`
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				baseURL+"/api/v2/projects/TEST?apiKey=dummy",
+				httpmock.NewStringResponder(200, `{"id":10,"projectKey":"TEST"}`),
+			)
+			httpmock.RegisterResponder(http.MethodGet, baseURL+"/api/v2/wikis/1?apiKey=dummy", httpmock.NewStringResponder(200, page))
+			var method, body string
+			record := func(req *http.Request) (*http.Response, error) {
+				method = req.Method
+				if req.Body != nil {
+					b, err := io.ReadAll(req.Body)
+					if err != nil {
+						return nil, err
+					}
+					body = string(b)
+				}
+				return httpmock.NewStringResponse(200, page), nil
+			}
+			httpmock.RegisterResponder(http.MethodPost, baseURL+"/api/v2/wikis?apiKey=dummy", record)
+			httpmock.RegisterResponder(http.MethodPatch, baseURL+"/api/v2/wikis/1?apiKey=dummy", record)
+			httpmock.RegisterResponder(http.MethodDelete, baseURL+"/api/v2/wikis/1?apiKey=dummy", record)
+
+			cmd := newCmd(io.Discard, io.Discard)
+			cmd.Reader = strings.NewReader(tt.stdin)
+			args := append([]string{name, "wiki"}, tt.args...)
+			err := cmd.Run(context.Background(), append(args, "--base-url", baseURL, "--api-key", "dummy"))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.method, method)
+			assert.Equal(t, tt.expected.body, body)
+		})
+	}
+}
+
+func Test_cli_export(t *testing.T) {
+	type expected struct {
+		files   map[string]string
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		list     string
+		expected expected
+	}{
+		{
+			name: "tree",
+			list: `[{"id":1,"projectId":10,"name":"Home"},{"id":2,"projectId":10,"name":"Design/API"}]`,
+			expected: expected{
+				files: map[string]string{
+					"Home.md":                         "---\nid: 1\nprojectId: 10\ntags: []\n---\nhome",
+					filepath.Join("Design", "API.md"): "---\nid: 2\nprojectId: 10\ntags: []\n---\napi",
+				},
+			},
+		},
+		{
+			name: "same file",
+			list: `[{"id":1,"projectId":10,"name":"a//b"},{"id":2,"projectId":10,"name":"a/_/b"}]`,
+			expected: expected{
+				files:   map[string]string{},
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			baseURL := "https://example.com"
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				baseURL+"/api/v2/wikis?apiKey=dummy&projectIdOrKey=TEST",
+				httpmock.NewStringResponder(200, tt.list),
+			)
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				baseURL+"/api/v2/wikis/1?apiKey=dummy",
+				httpmock.NewStringResponder(200, `{"id":1,"projectId":10,"name":"Home","content":"home"}`),
+			)
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				baseURL+"/api/v2/wikis/2?apiKey=dummy",
+				httpmock.NewStringResponder(200, `{"id":2,"projectId":10,"name":"Design/API","content":"api"}`),
+			)
+
+			dir := t.TempDir()
+			args := []string{name, "wiki", "export", "--base-url", baseURL, "--api-key", "dummy", "--project-key", "TEST", "--out", dir, "--concurrency", "2"}
+			err := newCmd(io.Discard, io.Discard).Run(context.Background(), args)
+			if tt.expected.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			actual := map[string]string{}
+			assert.NoError(t, filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+				if err != nil || d.IsDir() {
+					return err
+				}
+				b, err := os.ReadFile(path)
+				if err != nil {
+					return err
+				}
+				rel, err := filepath.Rel(dir, path)
+				if err != nil {
+					return err
+				}
+				actual[rel] = string(b)
+				return nil
+			}))
+			assert.Equal(t, tt.expected.files, actual)
+		})
+	}
+}
+
+func Test_cli_push(t *testing.T) {
+	type expected struct {
+		calls map[string]int
+	}
+	tests := []struct {
+		name     string
+		args     []string
+		expected expected
+	}{
+		{
+			name: "create and update",
+			args: []string{},
+			expected: expected{
+				calls: map[string]int{
+					"POST /api/v2/wikis":    1,
+					"PATCH /api/v2/wikis/1": 1,
+				},
+			},
+		},
+		{
+			name: "prune",
+			args: []string{"--prune"},
+			expected: expected{
+				calls: map[string]int{
+					"POST /api/v2/wikis":     1,
+					"PATCH /api/v2/wikis/1":  1,
+					"DELETE /api/v2/wikis/3": 1,
+				},
+			},
+		},
+		{
+			name: "dry run",
+			args: []string{"--prune", "--dry-run"},
+			expected: expected{
+				calls: map[string]int{},
+			},
+		},
+		{
+			name: "pattern",
+			args: []string{"--prune", "--pattern", "^Home$"},
+			expected: expected{
+				calls: map[string]int{
+					"PATCH /api/v2/wikis/1": 1,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			assert.NoError(t, os.WriteFile(filepath.Join(dir, "Home.md"), []byte("---\nid: 1\nprojectId: 10\ntags: []\n---\nnew home"), 0o600))
+			assert.NoError(t, os.MkdirAll(filepath.Join(dir, "Design"), 0o750))
+			assert.NoError(t, os.WriteFile(filepath.Join(dir, "Design", "API.md"), []byte("api"), 0o600))
+			assert.NoError(t, os.WriteFile(filepath.Join(dir, "Guide.md"), []byte("guide"), 0o600))
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			baseURL := "https://example.com"
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				baseURL+"/api/v2/projects/TEST?apiKey=dummy",
+				httpmock.NewStringResponder(200, `{"id":10,"projectKey":"TEST"}`),
+			)
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				baseURL+"/api/v2/wikis?apiKey=dummy&projectIdOrKey=TEST",
+				httpmock.NewStringResponder(200, `[{"id":1,"projectId":10,"name":"Home"},{"id":2,"projectId":10,"name":"Guide"},{"id":3,"projectId":10,"name":"Obsolete"}]`),
+			)
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				baseURL+"/api/v2/wikis/1?apiKey=dummy",
+				httpmock.NewStringResponder(200, `{"id":1,"projectId":10,"name":"Home","content":"old home"}`),
+			)
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				baseURL+"/api/v2/wikis/2?apiKey=dummy",
+				httpmock.NewStringResponder(200, `{"id":2,"projectId":10,"name":"Guide","content":"guide"}`),
+			)
+			for _, r := range []struct{ method, path string }{
+				{http.MethodPost, "/api/v2/wikis"},
+				{http.MethodPatch, "/api/v2/wikis/1"},
+				{http.MethodDelete, "/api/v2/wikis/3"},
+			} {
+				httpmock.RegisterResponder(r.method, baseURL+r.path+"?apiKey=dummy", httpmock.NewStringResponder(200, `{}`))
+			}
+
+			args := []string{name, "wiki", "push", "--base-url", baseURL, "--api-key", "dummy", "--project-key", "TEST", "--concurrency", "2"}
+			err := newCmd(io.Discard, io.Discard).Run(context.Background(), append(append(args, tt.args...), dir))
+			assert.NoError(t, err)
+
+			calls := map[string]int{}
+			for k, v := range httpmock.GetCallCountInfo() {
+				method, rest, _ := strings.Cut(k, " ")
+				if v > 0 && method != http.MethodGet {
+					calls[method+" "+strings.TrimSuffix(strings.TrimPrefix(rest, baseURL), "?apiKey=dummy")] = v
+				}
+			}
+			assert.Equal(t, tt.expected.calls, calls)
+		})
+	}
+}
+
+func Test_cli_pushReport(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected *wiki.RunReport
+	}{
+		{
+			name: "stop at first failure",
+			args: []string{},
+			expected: &wiki.RunReport{
+				Succeeded:   []*wiki.ReportEntry{{PageID: 2, Name: "Guide"}},
+				Failed:      []*wiki.ReportEntry{{PageID: 1, Name: "Home"}},
+				Interrupted: []*wiki.ReportEntry{},
+				Skipped:     []*wiki.ReportEntry{{Name: "Design/API"}, {PageID: 3, Name: "Obsolete"}},
+			},
+		},
+		{
+			name: "continue on error",
+			args: []string{"--continue-on-error"},
+			expected: &wiki.RunReport{
+				Succeeded:   []*wiki.ReportEntry{{PageID: 2, Name: "Guide"}, {Name: "Design/API"}, {PageID: 3, Name: "Obsolete"}},
+				Failed:      []*wiki.ReportEntry{{PageID: 1, Name: "Home"}},
+				Interrupted: []*wiki.ReportEntry{},
+				Skipped:     []*wiki.ReportEntry{},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			assert.NoError(t, os.WriteFile(filepath.Join(dir, "Home.md"), []byte("---\nid: 1\nprojectId: 10\ntags: []\n---\nnew home"), 0o600))
+			assert.NoError(t, os.MkdirAll(filepath.Join(dir, "Design"), 0o750))
+			assert.NoError(t, os.WriteFile(filepath.Join(dir, "Design", "API.md"), []byte("api"), 0o600))
+			assert.NoError(t, os.WriteFile(filepath.Join(dir, "Guide.md"), []byte("guide"), 0o600))
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			baseURL := "https://example.com"
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				baseURL+"/api/v2/projects/TEST?apiKey=dummy",
+				httpmock.NewStringResponder(200, `{"id":10,"projectKey":"TEST"}`),
+			)
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				baseURL+"/api/v2/wikis?apiKey=dummy&projectIdOrKey=TEST",
+				httpmock.NewStringResponder(200, `[{"id":1,"projectId":10,"name":"Home"},{"id":2,"projectId":10,"name":"Guide"},{"id":3,"projectId":10,"name":"Obsolete"}]`),
+			)
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				baseURL+"/api/v2/wikis/1?apiKey=dummy",
+				httpmock.NewStringResponder(400, `{"errors":[]}`),
+			)
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				baseURL+"/api/v2/wikis/2?apiKey=dummy",
+				httpmock.NewStringResponder(200, `{"id":2,"projectId":10,"name":"Guide","content":"guide"}`),
+			)
+			httpmock.RegisterResponder(http.MethodPost, baseURL+"/api/v2/wikis?apiKey=dummy", httpmock.NewStringResponder(200, `{}`))
+			httpmock.RegisterResponder(http.MethodDelete, baseURL+"/api/v2/wikis/3?apiKey=dummy", httpmock.NewStringResponder(200, `{}`))
+
+			report := filepath.Join(t.TempDir(), "report.json")
+			args := []string{name, "wiki", "push", "--base-url", baseURL, "--api-key", "dummy", "--project-key", "TEST", "--prune", "--report", report}
+			err := newCmd(io.Discard, io.Discard).Run(context.Background(), append(append(args, tt.args...), dir))
+			assert.Error(t, err)
+
+			b, err := os.ReadFile(report)
+			assert.NoError(t, err)
+			actual := &wiki.RunReport{}
+			assert.NoError(t, json.Unmarshal(b, actual))
+			for _, f := range actual.Failed {
+				assert.NotEmpty(t, f.Error)
+				f.Error = ""
+			}
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func Test_cli_history(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected map[string]int
+	}{
+		{
+			name: "history",
+			args: []string{"history", "--wiki-id", "1", "--order", "asc"},
+			expected: map[string]int{
+				"/api/v2/wikis/1/history?apiKey=dummy&count=100&order=asc": 1,
+			},
+		},
+		{
+			name: "diff revisions",
+			args: []string{"diff", "--wiki-id", "1", "--from", "1", "--to", "2"},
+			expected: map[string]int{
+				"/api/v2/wikis/1/history?apiKey=dummy&count=100": 2,
+			},
+		},
+		{
+			name: "diff current",
+			args: []string{"diff", "--wiki-id", "1", "--from", "2"},
+			expected: map[string]int{
+				"/api/v2/wikis/1/history?apiKey=dummy&count=100": 1,
+				"/api/v2/wikis/1?apiKey=dummy":                   1,
+			},
+		},
+		{
+			name: "restore",
+			args: []string{"restore", "--wiki-id", "1", "--version", "2", "--expected-version", "2", "--dry-run"},
+			expected: map[string]int{
+				"/api/v2/wikis/1/history?apiKey=dummy&count=1":   1,
+				"/api/v2/wikis/1/history?apiKey=dummy&count=100": 1,
+				"/api/v2/wikis/1?apiKey=dummy":                   1,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			baseURL := "https://example.com"
+			// The IDs of the history records differ from the versions.
+			revision := `{"id":%d,"pageId":1,"version":%d,"name":"Home","content":"v%d","createdUser":{"id":1,"userId":"user1","name":"User 1","roleType":1},"created":"2025-04-01T00:00:00Z"}`
+			for _, r := range []struct {
+				query    string
+				versions []int
+			}{
+				{"count=100&order=asc", []int{1, 2}},
+				{"count=100", []int{2, 1}},
+				{"count=1", []int{2}},
+			} {
+				items := make([]string, len(r.versions))
+				for i, v := range r.versions {
+					items[i] = fmt.Sprintf(revision, 10*v+3, v, v)
+				}
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					baseURL+"/api/v2/wikis/1/history?apiKey=dummy&"+r.query,
+					httpmock.NewStringResponder(200, "["+strings.Join(items, ",")+"]"),
+				)
+			}
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				baseURL+"/api/v2/wikis/1?apiKey=dummy",
+				httpmock.NewStringResponder(200, `{"id":1,"projectId":10,"name":"Top","content":"current"}`),
+			)
+
+			args := append([]string{name, "wiki"}, tt.args...)
+			err := newCmd(io.Discard, io.Discard).Run(context.Background(), append(args, "--base-url", baseURL, "--api-key", "dummy"))
+			assert.NoError(t, err)
+
+			calls := map[string]int{}
+			for k, v := range httpmock.GetCallCountInfo() {
+				if v > 0 {
+					calls[strings.TrimPrefix(k, http.MethodGet+" "+baseURL)] = v
+				}
+			}
+			assert.Equal(t, tt.expected, calls)
+		})
+	}
+}
+
+func Test_cli_tags(t *testing.T) {
+	type expected struct {
+		bodies    map[string]string
+		succeeded []int64
+	}
+	tests := []struct {
+		name     string
+		args     []string
+		expected expected
+	}{
+		{
+			name: "tags",
+			args: []string{"tags"},
+			expected: expected{
+				bodies: map[string]string{},
+			},
+		},
+		{
+			name: "add",
+			args: []string{"tag-all", "--pattern", "Design/", "--add", "draft"},
+			expected: expected{
+				bodies: map[string]string{
+					"/api/v2/wikis/1": "name=%5Bapi%5D%5Bdraft%5D+Design%2FAPI",
+					"/api/v2/wikis/2": "name=%5Bdraft%5D+Design%2FDB",
+				},
+				succeeded: []int64{1, 2},
+			},
+		},
+		{
+			name: "add existing",
+			args: []string{"tag-all", "--add", "api"},
+			expected: expected{
+				bodies: map[string]string{
+					"/api/v2/wikis/2": "name=%5Bapi%5D+Design%2FDB",
+				},
+				succeeded: []int64{1, 2, 3},
+			},
+		},
+		{
+			name: "remove by tag",
+			args: []string{"tag-all", "--tag", "api", "--remove", "api"},
+			expected: expected{
+				bodies: map[string]string{
+					"/api/v2/wikis/1": "name=Design%2FAPI",
+					"/api/v2/wikis/3": "name=Notes",
+				},
+				succeeded: []int64{1, 3},
+			},
+		},
+		{
+			name: "dry run",
+			args: []string{"tag-all", "--add", "draft", "--dry-run"},
+			expected: expected{
+				bodies:    map[string]string{},
+				succeeded: []int64{1, 2, 3},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			baseURL := "https://example.com"
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				baseURL+"/api/v2/wikis?apiKey=dummy&projectIdOrKey=TEST",
+				httpmock.NewStringResponder(200, `[{"id":1,"projectId":10,"name":"[api] Design/API","tags":[{"id":1,"name":"api"}]},`+
+					`{"id":2,"projectId":10,"name":"Design/DB"},`+
+					`{"id":3,"projectId":10,"name":"[api] Notes","tags":[{"id":1,"name":"api"}]}]`),
+			)
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				baseURL+"/api/v2/wikis/tags?apiKey=dummy&projectIdOrKey=TEST",
+				httpmock.NewStringResponder(200, `[{"id":1,"name":"api"}]`),
+			)
+			bodies := map[string]string{}
+			var mu sync.Mutex
+			record := func(req *http.Request) (*http.Response, error) {
+				b, err := io.ReadAll(req.Body)
+				if err != nil {
+					return nil, err
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				bodies[req.URL.Path] = string(b)
+				return httpmock.NewStringResponse(200, `{}`), nil
+			}
+			for _, id := range []int64{1, 2, 3} {
+				httpmock.RegisterResponder(http.MethodPatch, fmt.Sprintf("%s/api/v2/wikis/%d?apiKey=dummy", baseURL, id), record)
+			}
+
+			args := append([]string{name, "wiki"}, tt.args...)
+			args = append(args, "--base-url", baseURL, "--api-key", "dummy", "--project-key", "TEST")
+			report := filepath.Join(t.TempDir(), "report.json")
+			if tt.args[0] == "tag-all" {
+				args = append(args, "--journal", filepath.Join(t.TempDir(), "journal.jsonl"), "--concurrency", "2", "--report", report)
+			}
+			err := newCmd(io.Discard, io.Discard).Run(context.Background(), args)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.bodies, bodies)
+			if tt.args[0] == "tag-all" {
+				b, err := os.ReadFile(report)
+				assert.NoError(t, err)
+				actual := &wiki.RunReport{}
+				assert.NoError(t, json.Unmarshal(b, actual))
+				var succeeded []int64
+				for _, e := range actual.Succeeded {
+					succeeded = append(succeeded, e.PageID)
+				}
+				assert.Equal(t, tt.expected.succeeded, succeeded)
+			}
+			if tt.name == "tags" {
+				assert.Equal(t, 1, httpmock.GetCallCountInfo()["GET "+baseURL+"/api/v2/wikis/tags?apiKey=dummy&projectIdOrKey=TEST"])
+			}
+		})
+	}
+}
+
+func Test_cli_tagRollback(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	baseURL := "https://example.com"
+	httpmock.RegisterResponder(
+		http.MethodGet,
+		baseURL+"/api/v2/wikis?apiKey=dummy&projectIdOrKey=TEST",
+		httpmock.NewStringResponder(200, `[{"id":1,"projectId":10,"name":"[api] Design/API","tags":[{"id":1,"name":"api"}]},{"id":2,"projectId":10,"name":"Design/DB"}]`),
+	)
+	var bodies []string
+	var mu sync.Mutex
+	for _, id := range []int64{1, 2} {
+		httpmock.RegisterResponder(
+			http.MethodPatch,
+			fmt.Sprintf("%s/api/v2/wikis/%d?apiKey=dummy", baseURL, id),
+			func(req *http.Request) (*http.Response, error) {
+				b, err := io.ReadAll(req.Body)
+				if err != nil {
+					return nil, err
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				bodies = append(bodies, req.URL.Path+" "+string(b))
+				return httpmock.NewStringResponse(200, `{}`), nil
+			},
+		)
+	}
+
+	journal := filepath.Join(t.TempDir(), "journal.jsonl")
+	args := []string{name, "wiki", "tag-all", "--base-url", baseURL, "--api-key", "dummy", "--project-key", "TEST", "--add", "draft", "--journal", journal}
+	assert.NoError(t, newCmd(io.Discard, io.Discard).Run(context.Background(), args))
+	bodies = nil
+
+	args = []string{name, "wiki", "rollback", "--base-url", baseURL, "--api-key", "dummy", "--journal", journal}
+	assert.NoError(t, newCmd(io.Discard, io.Discard).Run(context.Background(), args))
+	assert.Equal(t, []string{
+		"/api/v2/wikis/2 name=Design%2FDB",
+		"/api/v2/wikis/1 name=%5Bapi%5D+Design%2FAPI",
+	}, bodies)
+}
+
+func Test_cli_attachments(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "spec.pdf")
+	assert.NoError(t, os.WriteFile(file, []byte("spec"), 0o600))
+
+	type expected struct {
+		calls   map[string]int
+		files   map[string]string
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		args     []string
+		list     string
+		existing map[string]string
+		expected expected
+	}{
+		{
+			name: "download all",
+			args: []string{"download"},
+			list: `[{"id":2,"name":"design.png","size":6},{"id":3,"name":"notes.txt","size":5}]`,
+			expected: expected{
+				calls: map[string]int{
+					"GET /api/v2/wikis/1/attachments":   1,
+					"GET /api/v2/wikis/1/attachments/2": 1,
+					"GET /api/v2/wikis/1/attachments/3": 1,
+				},
+				files: map[string]string{"design.png": "file 2", "notes.txt": "file 3"},
+			},
+		},
+		{
+			name: "download selected",
+			args: []string{"download", "--attachment-id", "3"},
+			list: `[{"id":2,"name":"design.png","size":6},{"id":3,"name":"notes.txt","size":5}]`,
+			expected: expected{
+				calls: map[string]int{
+					"GET /api/v2/wikis/1/attachments":   1,
+					"GET /api/v2/wikis/1/attachments/3": 1,
+				},
+				files: map[string]string{"notes.txt": "file 3"},
+			},
+		},
+		{
+			name: "download same file",
+			args: []string{"download"},
+			list: `[{"id":2,"name":"a/b.png","size":6},{"id":3,"name":"a_b.png","size":5}]`,
+			expected: expected{
+				calls:   map[string]int{"GET /api/v2/wikis/1/attachments": 1},
+				files:   map[string]string{},
+				isError: true,
+			},
+		},
+		{
+			name:     "download existing file",
+			args:     []string{"download"},
+			list:     `[{"id":2,"name":"design.png","size":6},{"id":3,"name":"notes.txt","size":5}]`,
+			existing: map[string]string{"notes.txt": "old"},
+			expected: expected{
+				calls:   map[string]int{"GET /api/v2/wikis/1/attachments": 1},
+				files:   map[string]string{"notes.txt": "old"},
+				isError: true,
+			},
+		},
+		{
+			name:     "download overwrite",
+			args:     []string{"download", "--overwrite"},
+			list:     `[{"id":2,"name":"design.png","size":6},{"id":3,"name":"notes.txt","size":5}]`,
+			existing: map[string]string{"notes.txt": "old"},
+			expected: expected{
+				calls: map[string]int{
+					"GET /api/v2/wikis/1/attachments":   1,
+					"GET /api/v2/wikis/1/attachments/2": 1,
+					"GET /api/v2/wikis/1/attachments/3": 1,
+				},
+				files: map[string]string{"design.png": "file 2", "notes.txt": "file 3"},
+			},
+		},
+		{
+			name: "upload",
+			args: []string{"upload", file},
+			expected: expected{
+				calls: map[string]int{
+					"POST /api/v2/space/attachment":    1,
+					"POST /api/v2/wikis/1/attachments": 1,
+				},
+				files: map[string]string{},
+			},
+		},
+		{
+			name: "upload dry run",
+			args: []string{"upload", "--dry-run", file},
+			expected: expected{
+				calls: map[string]int{},
+				files: map[string]string{},
+			},
+		},
+		{
+			name: "delete",
+			args: []string{"delete", "--attachment-id", "2"},
+			list: `[{"id":2,"name":"design.png","size":6}]`,
+			expected: expected{
+				calls: map[string]int{
+					"GET /api/v2/wikis/1/attachments":      1,
+					"DELETE /api/v2/wikis/1/attachments/2": 1,
+				},
+				files: map[string]string{},
+			},
+		},
+		{
+			name: "delete dry run",
+			args: []string{"delete", "--attachment-id", "2", "--dry-run"},
+			list: `[{"id":2,"name":"design.png","size":6}]`,
+			expected: expected{
+				calls: map[string]int{"GET /api/v2/wikis/1/attachments": 1},
+				files: map[string]string{},
+			},
+		},
+		{
+			name: "delete unknown attachment",
+			args: []string{"delete", "--attachment-id", "9"},
+			list: `[{"id":2,"name":"design.png","size":6}]`,
+			expected: expected{
+				calls:   map[string]int{"GET /api/v2/wikis/1/attachments": 1},
+				files:   map[string]string{},
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			baseURL := "https://example.com"
+			httpmock.RegisterResponder(http.MethodGet, baseURL+"/api/v2/wikis/1/attachments?apiKey=dummy", httpmock.NewStringResponder(200, tt.list))
+			for _, id := range []int64{2, 3} {
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					fmt.Sprintf("%s/api/v2/wikis/1/attachments/%d?apiKey=dummy", baseURL, id),
+					httpmock.NewStringResponder(200, fmt.Sprintf("file %d", id)),
+				)
+			}
+			httpmock.RegisterResponder(http.MethodPost, baseURL+"/api/v2/space/attachment?apiKey=dummy", httpmock.NewStringResponder(200, `{"id":5,"name":"spec.pdf","size":4}`))
+			httpmock.RegisterResponder(http.MethodPost, baseURL+"/api/v2/wikis/1/attachments?apiKey=dummy", httpmock.NewStringResponder(200, `[{"id":4,"name":"spec.pdf","size":4}]`))
+			httpmock.RegisterResponder(http.MethodDelete, baseURL+"/api/v2/wikis/1/attachments/2?apiKey=dummy", httpmock.NewStringResponder(200, `{"id":2,"name":"design.png","size":6}`))
+
+			out := t.TempDir()
+			for f, content := range tt.existing {
+				assert.NoError(t, os.WriteFile(filepath.Join(out, f), []byte(content), 0o600))
+			}
+			// The files to upload are arguments, so the options of each case come last.
+			args := []string{name, "wiki", "attachments", tt.args[0], "--base-url", baseURL, "--api-key", "dummy", "--wiki-id", "1"}
+			if tt.args[0] == "download" {
+				args = append(args, "--out", out)
+			}
+			args = append(args, tt.args[1:]...)
+			err := newCmd(io.Discard, io.Discard).Run(context.Background(), args)
+			if tt.expected.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			calls := map[string]int{}
+			for k, v := range httpmock.GetCallCountInfo() {
+				method, rest, _ := strings.Cut(k, " ")
+				if v > 0 {
+					calls[method+" "+strings.TrimSuffix(strings.TrimPrefix(rest, baseURL), "?apiKey=dummy")] = v
+				}
+			}
+			assert.Equal(t, tt.expected.calls, calls)
+
+			files := map[string]string{}
+			entries, err := os.ReadDir(out)
+			assert.NoError(t, err)
+			for _, e := range entries {
+				b, err := os.ReadFile(filepath.Join(out, e.Name()))
+				assert.NoError(t, err)
+				files[e.Name()] = string(b)
+			}
+			assert.Equal(t, tt.expected.files, files)
+		})
+	}
+}
+
+func Test_cli_profile(t *testing.T) {
+	type expected struct {
+		calls   int
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		args     []string
+		expected expected
+	}{
+		{
+			name: "default profile",
+			args: []string{name, "wiki", "list"},
+			expected: expected{
+				calls: 1,
+			},
+		},
+		{
+			name: "named profile",
+			args: []string{name, "--profile", "work", "wiki", "list"},
+			expected: expected{
+				calls: 1,
+			},
+		},
+		{
+			name: "flag takes precedence",
+			args: []string{name, "wiki", "list", "--project-key", "OTHER"},
+			expected: expected{
+				calls: 1,
+			},
+		},
+		{
+			// The issue commands take the project keys with a flag of their own, which the profile fills as well.
+			name: "issue command",
+			args: []string{name, "issue", "count"},
+			expected: expected{
+				calls: 2,
+			},
+		},
+		{
+			name: "unknown profile",
+			args: []string{name, "--profile", "unknown", "wiki", "list"},
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name: "profile without project key",
+			args: []string{name, "--profile", "empty", "wiki", "list"},
+			expected: expected{
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.toml")
+			t.Setenv("BACKLOG_CONFIG", path)
+			t.Setenv("BKL_TEST_API_KEY", "dummy")
+			for _, args := range [][]string{
+				{"work", "base_url", "https://example.com"},
+				{"work", "api_key_env", "BKL_TEST_API_KEY"},
+				{"work", "project_key", "TEST"},
+				{"empty", "base_url", "https://example.com"},
+			} {
+				assert.NoError(t, newCmd(io.Discard, io.Discard).Run(context.Background(), append([]string{name, "config", "set"}, args...)))
+			}
+			assert.NoError(t, newCmd(io.Discard, io.Discard).Run(context.Background(), []string{name, "config", "use", "work"}))
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			for _, key := range []string{"TEST", "OTHER"} {
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					"https://example.com/api/v2/wikis?apiKey=dummy&projectIdOrKey="+key,
+					httpmock.NewStringResponder(200, `[{"id":1,"name":"a"}]`),
+				)
+			}
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				"https://example.com/api/v2/projects/TEST?apiKey=dummy",
+				httpmock.NewStringResponder(200, `{"id":10,"projectKey":"TEST"}`),
+			)
+			httpmock.RegisterResponderWithQuery(
+				http.MethodGet,
+				"https://example.com/api/v2/issues/count",
+				"apiKey=dummy&projectId%5B%5D=10",
+				httpmock.NewStringResponder(200, `{"count":1}`),
+			)
+
+			err := newCmd(io.Discard, io.Discard).Run(context.Background(), tt.args)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.calls, httpmock.GetTotalCallCount())
+		})
+	}
+}
+
+func Test_cli_config(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	t.Setenv("BACKLOG_CONFIG", path)
+	run := func(args ...string) error {
+		return newCmd(io.Discard, io.Discard).Run(context.Background(), append([]string{name, "config"}, args...))
+	}
+
+	assert.NoError(t, run("set", "work", "api_key", "secret"))
+	assert.NoError(t, run("set", "home", "retry.max_attempts", "3"))
+	assert.NoError(t, run("use", "work"))
+	assert.NoError(t, run("list"))
+	assert.NoError(t, run("show"))
+	assert.NoError(t, run("show", "home"))
+
+	c, err := config.Load(path)
+	assert.NoError(t, err)
+	assert.Equal(t, &config.Config{
+		DefaultProfile: "work",
+		Profiles: map[string]*config.Profile{
+			"work": {APIKey: "secret"},
+			"home": {Retry: &config.Retry{MaxAttempts: 3}},
+		},
+	}, c)
+
+	assert.NoError(t, run("delete", "work"))
+	c, err = config.Load(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"home"}, c.Names())
+	assert.Empty(t, c.DefaultProfile)
+
+	assert.Error(t, run("show"))
+	assert.Error(t, run("show", "home", "work"))
+	assert.Error(t, run("set", "home", "unknown", "value"))
+	assert.Error(t, run("set", "home", "retry.max_attempts", "x"))
+	assert.Error(t, run("set", "home"))
+	assert.Error(t, run("use", "work"))
+	assert.Error(t, run("delete", "work"))
+}
+
+func Test_cli_retry(t *testing.T) {
+	type expected struct {
+		calls   int
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		profile  [][]string
+		args     []string
+		expected expected
+	}{
+		{
+			name: "max attempts",
+			args: []string{"--retry-max-attempts", "2"},
+			expected: expected{
+				calls:   2,
+				isError: true,
+			},
+		},
+		{
+			name: "status not retried",
+			args: []string{"--retry-max-attempts", "2", "--retry-status", "500"},
+			expected: expected{
+				calls:   1,
+				isError: true,
+			},
+		},
+		{
+			name: "zero jitter",
+			args: []string{"--retry-max-attempts", "2", "--retry-max-jitter-ms", "0"},
+			expected: expected{
+				calls:   2,
+				isError: true,
+			},
+		},
+		{
+			name: "negative jitter",
+			args: []string{"--retry-max-jitter-ms", "-1"},
+			expected: expected{
+				calls:   0,
+				isError: true,
+			},
+		},
+		{
+			name: "zero attempts",
+			args: []string{"--retry-max-attempts", "0"},
+			expected: expected{
+				calls:   0,
+				isError: true,
+			},
+		},
+		{
+			name: "negative interval",
+			args: []string{"--retry-max-interval", "-1s"},
+			expected: expected{
+				calls:   0,
+				isError: true,
+			},
+		},
+		{
+			name:    "profile",
+			profile: [][]string{{"retry.max_attempts", "3"}},
+			expected: expected{
+				calls:   3,
+				isError: true,
+			},
+		},
+		{
+			name:    "flag takes precedence over profile",
+			profile: [][]string{{"retry.max_attempts", "3"}, {"retry.statuses", "503"}},
+			args:    []string{"--retry-max-attempts", "4"},
+			expected: expected{
+				calls:   4,
+				isError: true,
+			},
+		},
+		{
+			name:    "profile statuses",
+			profile: [][]string{{"retry.max_attempts", "3"}, {"retry.statuses", "500,502"}},
+			expected: expected{
+				calls:   1,
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BACKLOG_CONFIG", filepath.Join(t.TempDir(), "config.toml"))
+			for _, kv := range tt.profile {
+				assert.NoError(t, newCmd(io.Discard, io.Discard).Run(context.Background(), append([]string{name, "config", "set", "work"}, kv...)))
+			}
+			if tt.profile != nil {
+				assert.NoError(t, newCmd(io.Discard, io.Discard).Run(context.Background(), []string{name, "config", "use", "work"}))
+			}
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			httpmock.RegisterResponder(
+				http.MethodGet,
+				"https://example.com/api/v2/wikis?apiKey=dummy&projectIdOrKey=TEST",
+				httpmock.NewStringResponder(503, `{"errors":[]}`),
+			)
+
+			args := []string{
+				name, "--retry-initial-interval", "1ms", "--retry-max-jitter-ms", "1",
+				"wiki", "list", "--base-url", "https://example.com", "--api-key", "dummy", "--project-key", "TEST",
+			}
+			err := newCmd(io.Discard, io.Discard).Run(context.Background(), append(args, tt.args...))
+			if tt.expected.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expected.calls, httpmock.GetTotalCallCount())
+		})
+	}
+}
@@ -3,13 +3,17 @@ package main
 import (
 	"context"
 	"os"
+	"os/signal"
+	"syscall"
 )
 
 func main() {
-	ctx := context.Background()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	cmd := newCmd(os.Stdout, os.Stderr)
 	if err := cmd.Run(ctx, os.Args); err != nil {
+		stop()
 		logger.Error(err.Error())
 		os.Exit(1)
 	}
+	stop()
 }
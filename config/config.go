@@ -0,0 +1,409 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Config represents the configuration file with named profiles.
+type Config struct {
+	DefaultProfile string              `toml:"default_profile,omitempty"`
+	Profiles       map[string]*Profile `toml:"profiles,omitempty"`
+}
+
+// Profile represents the settings of a Backlog space.
+// Credentials are read from the *_env environment variables if they are not set directly.
+type Profile struct {
+	BaseURL         string `toml:"base_url,omitempty"`
+	APIKey          string `toml:"api_key,omitempty"`
+	APIKeyEnv       string `toml:"api_key_env,omitempty"`
+	ClientID        string `toml:"client_id,omitempty"`
+	ClientSecret    string `toml:"client_secret,omitempty"`
+	ClientSecretEnv string `toml:"client_secret_env,omitempty"`
+	TokenFile       string `toml:"token_file,omitempty"`
+	ProjectKey      string `toml:"project_key,omitempty"`
+	LogLevel        string `toml:"log_level,omitempty"`
+	Retry           *Retry `toml:"retry,omitempty"`
+}
+
+// Retry represents the retry settings of a profile. Zero values mean the defaults,
+// except for MaxJitterMilli, which is nil if it is not set so that 0 disables the jitter.
+type Retry struct {
+	InitialInterval time.Duration `toml:"initial_interval,omitzero"`
+	MaxInterval     time.Duration `toml:"max_interval,omitzero"`
+	MaxAttempts     int           `toml:"max_attempts,omitzero"`
+	MaxJitterMilli  *int          `toml:"max_jitter_ms,omitempty"`
+	Statuses        []int         `toml:"statuses,omitempty"`
+	NetworkErrors   *bool         `toml:"network_errors,omitempty"`
+	IdempotentOnly  bool          `toml:"idempotent_only,omitzero"`
+}
+
+// validate returns an error if a setting is out of range. MaxAttempts of 0 is not an error
+// because it cannot be told apart from the default.
+func (r *Retry) validate() error {
+	switch {
+	case r.InitialInterval < 0:
+		return fmt.Errorf("invalid retry.initial_interval: must not be negative: %s", r.InitialInterval)
+	case r.MaxInterval < 0:
+		return fmt.Errorf("invalid retry.max_interval: must not be negative: %s", r.MaxInterval)
+	case r.MaxAttempts < 0:
+		return fmt.Errorf("invalid retry.max_attempts: must be at least 1: %d", r.MaxAttempts)
+	case r.MaxJitterMilli != nil && *r.MaxJitterMilli < 0:
+		return fmt.Errorf("invalid retry.max_jitter_ms: must not be negative: %d", *r.MaxJitterMilli)
+	}
+	return nil
+}
+
+func (r *Retry) isZero() bool {
+	return r.InitialInterval == 0 && r.MaxInterval == 0 && r.MaxAttempts == 0 && r.MaxJitterMilli == nil &&
+		len(r.Statuses) == 0 && r.NetworkErrors == nil && !r.IdempotentOnly
+}
+
+// Keys returns the keys accepted by Profile.Set.
+func Keys() []string {
+	return []string{
+		"base_url", "api_key", "api_key_env", "client_id", "client_secret", "client_secret_env", "token_file",
+		"project_key", "log_level", "retry.initial_interval", "retry.max_interval", "retry.max_attempts", "retry.max_jitter_ms",
+		"retry.statuses", "retry.network_errors", "retry.idempotent_only",
+	}
+}
+
+// DefaultPath returns the default path of the configuration file.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "bkl", "config.toml"), nil
+}
+
+// Load reads the configuration file. It returns an empty configuration if the file does not exist.
+func Load(path string) (*Config, error) {
+	c := &Config{}
+	b, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return c, nil
+		}
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	md, err := toml.Decode(string(b), c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode config: %w", err)
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		return nil, fmt.Errorf("unknown config key: %s", undecoded[0])
+	}
+	for name, p := range c.Profiles {
+		if p == nil || p.Retry == nil {
+			continue
+		}
+		if err := p.Retry.validate(); err != nil {
+			return nil, fmt.Errorf("failed to load config: profile %s: %w", name, err)
+		}
+	}
+	return c, nil
+}
+
+// Save writes the configuration file that only the owner can read, creating the parent directory if needed.
+func (c *Config) Save(path string) error {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(c); err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	f, err := os.CreateTemp(dir, ".config-*")
+	if err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return nil
+}
+
+// Profile returns the named profile, or the default profile if name is empty.
+// It returns nil without error if name is empty and there is no default profile.
+func (c *Config) Profile(name string) (*Profile, error) {
+	if name == "" {
+		name = c.DefaultProfile
+		if name == "" {
+			return nil, nil
+		}
+	}
+	p, ok := c.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("no such profile: %s", name)
+	}
+	return p, nil
+}
+
+// Names returns the sorted names of the profiles.
+func (c *Config) Names() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Set sets the key of the named profile, creating the profile if it does not exist.
+// An empty value clears the key.
+func (c *Config) Set(name, key, value string) error {
+	if name == "" {
+		return errors.New("empty profile name")
+	}
+	p := c.Profiles[name]
+	if p == nil {
+		p = &Profile{}
+	}
+	if err := p.Set(key, value); err != nil {
+		return err
+	}
+	if c.Profiles == nil {
+		c.Profiles = map[string]*Profile{}
+	}
+	c.Profiles[name] = p
+	return nil
+}
+
+// Delete deletes the named profile and clears the default profile if it was the one.
+func (c *Config) Delete(name string) error {
+	if _, ok := c.Profiles[name]; !ok {
+		return fmt.Errorf("no such profile: %s", name)
+	}
+	delete(c.Profiles, name)
+	if c.DefaultProfile == name {
+		c.DefaultProfile = ""
+	}
+	return nil
+}
+
+// Use makes the named profile the default profile.
+func (c *Config) Use(name string) error {
+	if _, ok := c.Profiles[name]; !ok {
+		return fmt.Errorf("no such profile: %s", name)
+	}
+	c.DefaultProfile = name
+	return nil
+}
+
+// Set sets the key of the profile. An empty value clears the key.
+func (p *Profile) Set(key, value string) error {
+	switch key {
+	case "base_url":
+		p.BaseURL = value
+	case "api_key":
+		p.APIKey = value
+	case "api_key_env":
+		p.APIKeyEnv = value
+	case "client_id":
+		p.ClientID = value
+	case "client_secret":
+		p.ClientSecret = value
+	case "client_secret_env":
+		p.ClientSecretEnv = value
+	case "token_file":
+		p.TokenFile = value
+	case "project_key":
+		p.ProjectKey = value
+	case "log_level":
+		p.LogLevel = value
+	case "retry.initial_interval", "retry.max_interval":
+		d, err := parseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", key, err)
+		}
+		r := p.retry()
+		if key == "retry.initial_interval" {
+			r.InitialInterval = d
+		} else {
+			r.MaxInterval = d
+		}
+	case "retry.max_attempts", "retry.max_jitter_ms":
+		n, err := parseInt(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", key, err)
+		}
+		r := p.retry()
+		if key == "retry.max_attempts" {
+			if value != "" && n < 1 {
+				return fmt.Errorf("invalid %s: must be at least 1: %d", key, n)
+			}
+			r.MaxAttempts = n
+		} else {
+			r.MaxJitterMilli = nil
+			if value != "" {
+				r.MaxJitterMilli = &n
+			}
+		}
+	case "retry.statuses":
+		statuses, err := parseInts(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", key, err)
+		}
+		p.retry().Statuses = statuses
+	case "retry.network_errors", "retry.idempotent_only":
+		b, err := parseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", key, err)
+		}
+		r := p.retry()
+		if key == "retry.network_errors" {
+			r.NetworkErrors = nil
+			if value != "" {
+				r.NetworkErrors = &b
+			}
+		} else {
+			r.IdempotentOnly = b
+		}
+	default:
+		return fmt.Errorf("unknown config key: %s", key)
+	}
+	if p.Retry != nil && p.Retry.isZero() {
+		p.Retry = nil
+	}
+	if p.Retry != nil {
+		return p.Retry.validate()
+	}
+	return nil
+}
+
+// Values returns the settings of the profile keyed by the corresponding command line flag names.
+// Credentials in environment variables are resolved, and empty settings are omitted.
+func (p *Profile) Values() map[string]string {
+	apiKey := p.APIKey
+	if apiKey == "" && p.APIKeyEnv != "" {
+		apiKey = os.Getenv(p.APIKeyEnv)
+	}
+	clientSecret := p.ClientSecret
+	if clientSecret == "" && p.ClientSecretEnv != "" {
+		clientSecret = os.Getenv(p.ClientSecretEnv)
+	}
+	values := map[string]string{
+		"base-url":      p.BaseURL,
+		"api-key":       apiKey,
+		"client-id":     p.ClientID,
+		"client-secret": clientSecret,
+		"token-file":    p.TokenFile,
+		"project-key":   p.ProjectKey,
+		"log-level":     p.LogLevel,
+	}
+	if r := p.Retry; r != nil {
+		if r.InitialInterval != 0 {
+			values["retry-initial-interval"] = r.InitialInterval.String()
+		}
+		if r.MaxInterval != 0 {
+			values["retry-max-interval"] = r.MaxInterval.String()
+		}
+		if r.MaxAttempts != 0 {
+			values["retry-max-attempts"] = strconv.Itoa(r.MaxAttempts)
+		}
+		if r.MaxJitterMilli != nil {
+			values["retry-max-jitter-ms"] = strconv.Itoa(*r.MaxJitterMilli)
+		}
+		statuses := make([]string, len(r.Statuses))
+		for i, status := range r.Statuses {
+			statuses[i] = strconv.Itoa(status)
+		}
+		values["retry-status"] = strings.Join(statuses, ",")
+		if r.NetworkErrors != nil {
+			values["retry-network-errors"] = strconv.FormatBool(*r.NetworkErrors)
+		}
+		if r.IdempotentOnly {
+			values["retry-idempotent-only"] = "true"
+		}
+	}
+	for k, v := range values {
+		if v == "" {
+			delete(values, k)
+		}
+	}
+	return values
+}
+
+// Redacted returns a copy of the profile with the credentials stored in the file replaced.
+func (p *Profile) Redacted() *Profile {
+	o := *p
+	if o.APIKey != "" {
+		o.APIKey = "REDACTED"
+	}
+	if o.ClientSecret != "" {
+		o.ClientSecret = "REDACTED"
+	}
+	return &o
+}
+
+// Encode writes the profile to w in TOML.
+func (p *Profile) Encode(w io.Writer) error {
+	return toml.NewEncoder(w).Encode(p)
+}
+
+func (p *Profile) retry() *Retry {
+	if p.Retry == nil {
+		p.Retry = &Retry{}
+	}
+	return p.Retry
+}
+
+func parseDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(s)
+}
+
+func parseInt(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(s)
+}
+
+func parseInts(s string) ([]int, error) {
+	if s == "" {
+		return nil, nil
+	}
+	fields := strings.Split(s, ",")
+	ns := make([]int, len(fields))
+	for i, f := range fields {
+		n, err := strconv.Atoi(strings.TrimSpace(f))
+		if err != nil {
+			return nil, err
+		}
+		ns[i] = n
+	}
+	return ns, nil
+}
+
+func parseBool(s string) (bool, error) {
+	if s == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(s)
+}
@@ -0,0 +1,402 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	type expected struct {
+		config  *Config
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		content  string
+		expected expected
+	}{
+		{
+			name: "profiles",
+			content: `default_profile = "work"
+
+[profiles.work]
+base_url = "https://example.backlog.com"
+api_key_env = "WORK_API_KEY"
+project_key = "PROJ"
+log_level = "DEBUG"
+
+[profiles.work.retry]
+initial_interval = "500ms"
+max_attempts = 3
+statuses = [429, 503]
+network_errors = true
+`,
+			expected: expected{
+				config: &Config{
+					DefaultProfile: "work",
+					Profiles: map[string]*Profile{
+						"work": {
+							BaseURL:    "https://example.backlog.com",
+							APIKeyEnv:  "WORK_API_KEY",
+							ProjectKey: "PROJ",
+							LogLevel:   "DEBUG",
+							Retry: &Retry{
+								InitialInterval: 500 * time.Millisecond,
+								MaxAttempts:     3,
+								Statuses:        []int{429, 503},
+								NetworkErrors:   new(true),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "unknown key",
+			content: "[profiles.work]\nbase = \"https://example.backlog.com\"\n",
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name:    "negative retry value",
+			content: "[profiles.work.retry]\nmax_jitter_ms = -1\n",
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name:    "invalid toml",
+			content: "[profiles.work",
+			expected: expected{
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.toml")
+			assert.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+			actual, err := Load(path)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.config, actual)
+		})
+	}
+}
+
+func TestConfig_Save(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bkl", "config.toml")
+
+	c, err := Load(path)
+	assert.NoError(t, err)
+	assert.Equal(t, &Config{}, c)
+
+	assert.NoError(t, c.Set("work", "base_url", "https://example.backlog.com"))
+	assert.NoError(t, c.Set("work", "retry.max_interval", "10s"))
+	assert.NoError(t, c.Use("work"))
+	assert.NoError(t, c.Save(path))
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	actual, err := Load(path)
+	assert.NoError(t, err)
+	assert.Equal(t, c, actual)
+}
+
+func TestConfig_Profile(t *testing.T) {
+	c := &Config{
+		Profiles: map[string]*Profile{
+			"work": {BaseURL: "https://work.backlog.com"},
+			"home": {BaseURL: "https://home.backlog.com"},
+		},
+	}
+
+	p, err := c.Profile("")
+	assert.NoError(t, err)
+	assert.Nil(t, p)
+
+	p, err = c.Profile("home")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://home.backlog.com", p.BaseURL)
+
+	assert.NoError(t, c.Use("work"))
+	p, err = c.Profile("")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://work.backlog.com", p.BaseURL)
+
+	_, err = c.Profile("unknown")
+	assert.Error(t, err)
+	assert.Error(t, c.Use("unknown"))
+	assert.Equal(t, []string{"home", "work"}, c.Names())
+
+	assert.NoError(t, c.Delete("work"))
+	assert.Empty(t, c.DefaultProfile)
+	assert.Error(t, c.Delete("work"))
+	assert.Equal(t, []string{"home"}, c.Names())
+}
+
+func TestProfile_Set(t *testing.T) {
+	type args struct {
+		key   string
+		value string
+	}
+	type expected struct {
+		profile *Profile
+		isError bool
+	}
+	tests := []struct {
+		name     string
+		profile  *Profile
+		args     args
+		expected expected
+	}{
+		{
+			name:    "string",
+			profile: &Profile{},
+			args:    args{key: "client_id", value: "id"},
+			expected: expected{
+				profile: &Profile{ClientID: "id"},
+			},
+		},
+		{
+			name:    "clear string",
+			profile: &Profile{ProjectKey: "PROJ"},
+			args:    args{key: "project_key", value: ""},
+			expected: expected{
+				profile: &Profile{},
+			},
+		},
+		{
+			name:    "duration",
+			profile: &Profile{},
+			args:    args{key: "retry.initial_interval", value: "2s"},
+			expected: expected{
+				profile: &Profile{Retry: &Retry{InitialInterval: 2 * time.Second}},
+			},
+		},
+		{
+			name:    "int",
+			profile: &Profile{},
+			args:    args{key: "retry.max_jitter_ms", value: "100"},
+			expected: expected{
+				profile: &Profile{Retry: &Retry{MaxJitterMilli: new(100)}},
+			},
+		},
+		{
+			name:    "zero jitter",
+			profile: &Profile{},
+			args:    args{key: "retry.max_jitter_ms", value: "0"},
+			expected: expected{
+				profile: &Profile{Retry: &Retry{MaxJitterMilli: new(0)}},
+			},
+		},
+		{
+			name:    "clear jitter",
+			profile: &Profile{Retry: &Retry{MaxJitterMilli: new(0)}},
+			args:    args{key: "retry.max_jitter_ms", value: ""},
+			expected: expected{
+				profile: &Profile{},
+			},
+		},
+		{
+			name:    "statuses",
+			profile: &Profile{},
+			args:    args{key: "retry.statuses", value: "429, 503"},
+			expected: expected{
+				profile: &Profile{Retry: &Retry{Statuses: []int{429, 503}}},
+			},
+		},
+		{
+			name:    "bool",
+			profile: &Profile{},
+			args:    args{key: "retry.network_errors", value: "true"},
+			expected: expected{
+				profile: &Profile{Retry: &Retry{NetworkErrors: new(true)}},
+			},
+		},
+		{
+			name:    "false",
+			profile: &Profile{},
+			args:    args{key: "retry.network_errors", value: "false"},
+			expected: expected{
+				profile: &Profile{Retry: &Retry{NetworkErrors: new(false)}},
+			},
+		},
+		{
+			name:    "clear bool",
+			profile: &Profile{Retry: &Retry{NetworkErrors: new(false)}},
+			args:    args{key: "retry.network_errors", value: ""},
+			expected: expected{
+				profile: &Profile{},
+			},
+		},
+		{
+			name:    "clear last retry setting",
+			profile: &Profile{Retry: &Retry{MaxAttempts: 3}},
+			args:    args{key: "retry.max_attempts", value: ""},
+			expected: expected{
+				profile: &Profile{},
+			},
+		},
+		{
+			name:    "invalid duration",
+			profile: &Profile{},
+			args:    args{key: "retry.max_interval", value: "10"},
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name:    "invalid statuses",
+			profile: &Profile{},
+			args:    args{key: "retry.statuses", value: "429,abc"},
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name:    "invalid bool",
+			profile: &Profile{},
+			args:    args{key: "retry.idempotent_only", value: "maybe"},
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name:    "invalid int",
+			profile: &Profile{},
+			args:    args{key: "retry.max_attempts", value: "many"},
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name:    "zero attempts",
+			profile: &Profile{},
+			args:    args{key: "retry.max_attempts", value: "0"},
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name:    "negative jitter",
+			profile: &Profile{},
+			args:    args{key: "retry.max_jitter_ms", value: "-1"},
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name:    "negative interval",
+			profile: &Profile{},
+			args:    args{key: "retry.initial_interval", value: "-1s"},
+			expected: expected{
+				isError: true,
+			},
+		},
+		{
+			name:    "unknown key",
+			profile: &Profile{},
+			args:    args{key: "unknown", value: "value"},
+			expected: expected{
+				isError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.profile.Set(tt.args.key, tt.args.value)
+			if tt.expected.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.profile, tt.profile)
+		})
+	}
+}
+
+func TestProfile_Values(t *testing.T) {
+	t.Setenv("BKL_TEST_API_KEY", "env-key")
+	t.Setenv("BKL_TEST_CLIENT_SECRET", "")
+
+	tests := []struct {
+		name     string
+		profile  *Profile
+		expected map[string]string
+	}{
+		{
+			name: "direct values",
+			profile: &Profile{
+				BaseURL:    "https://example.backlog.com",
+				APIKey:     "key",
+				APIKeyEnv:  "BKL_TEST_API_KEY",
+				ProjectKey: "PROJ",
+			},
+			expected: map[string]string{
+				"base-url":    "https://example.backlog.com",
+				"api-key":     "key",
+				"project-key": "PROJ",
+			},
+		},
+		{
+			name: "environment variables",
+			profile: &Profile{
+				APIKeyEnv:       "BKL_TEST_API_KEY",
+				ClientID:        "id",
+				ClientSecretEnv: "BKL_TEST_CLIENT_SECRET",
+				LogLevel:        "DEBUG",
+			},
+			expected: map[string]string{
+				"api-key":   "env-key",
+				"client-id": "id",
+				"log-level": "DEBUG",
+			},
+		},
+		{
+			name: "retry",
+			profile: &Profile{
+				Retry: &Retry{
+					InitialInterval: 500 * time.Millisecond,
+					MaxAttempts:     3,
+					MaxJitterMilli:  new(0),
+					Statuses:        []int{429, 503},
+					NetworkErrors:   new(false),
+					IdempotentOnly:  true,
+				},
+			},
+			expected: map[string]string{
+				"retry-initial-interval": "500ms",
+				"retry-max-attempts":     "3",
+				"retry-max-jitter-ms":    "0",
+				"retry-status":           "429,503",
+				"retry-network-errors":   "false",
+				"retry-idempotent-only":  "true",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.profile.Values())
+		})
+	}
+}
+
+func TestProfile_Redacted(t *testing.T) {
+	p := &Profile{APIKey: "key", ClientID: "id", ClientSecret: "secret", ClientSecretEnv: "SECRET"}
+
+	var buf bytes.Buffer
+	assert.NoError(t, p.Redacted().Encode(&buf))
+	assert.Equal(t, "api_key = \"REDACTED\"\nclient_id = \"id\"\nclient_secret = \"REDACTED\"\nclient_secret_env = \"SECRET\"\n", buf.String())
+	assert.Equal(t, "key", p.APIKey)
+}
@@ -0,0 +1,108 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnified(t *testing.T) {
+	type args struct {
+		a string
+		b string
+	}
+	type expected struct {
+		value string
+	}
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "equal",
+			args: args{
+				a: "a\nb\n",
+				b: "a\nb\n",
+			},
+			expected: expected{
+				value: "",
+			},
+		},
+		{
+			name: "replace",
+			args: args{
+				a: "a\nb\nc\n",
+				b: "a\nB\nc\n",
+			},
+			expected: expected{
+				value: "--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+			},
+		},
+		{
+			name: "insert into empty",
+			args: args{
+				a: "",
+				b: "a\nb\n",
+			},
+			expected: expected{
+				value: "--- old\n+++ new\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+			},
+		},
+		{
+			name: "delete all",
+			args: args{
+				a: "a\n",
+				b: "",
+			},
+			expected: expected{
+				value: "--- old\n+++ new\n@@ -1 +0,0 @@\n-a\n",
+			},
+		},
+		{
+			name: "no newline at end of file",
+			args: args{
+				a: "a\nb",
+				b: "a\nc",
+			},
+			expected: expected{
+				value: "--- old\n+++ new\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+c\n\\ No newline at end of file\n",
+			},
+		},
+		{
+			name: "separate hunks",
+			args: args{
+				a: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+				b: "one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\ntwelve\n",
+			},
+			expected: expected{
+				value: "--- old\n+++ new\n@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n@@ -9,4 +9,4 @@\n 9\n 10\n 11\n-12\n+twelve\n",
+			},
+		},
+		{
+			name: "merged hunks",
+			args: args{
+				a: "1\n2\n3\n4\n5\n6\n7\n8\n",
+				b: "one\n2\n3\n4\n5\n6\n7\neight\n",
+			},
+			expected: expected{
+				value: "--- old\n+++ new\n@@ -1,8 +1,8 @@\n-1\n+one\n 2\n 3\n 4\n 5\n 6\n 7\n-8\n+eight\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := Unified("old", "new", tt.args.a, tt.args.b)
+			assert.Equal(t, tt.expected.value, actual)
+		})
+	}
+}
+
+func TestUnified_Large(t *testing.T) {
+	a := strings.Repeat("a\n", 5000) + "x\n"
+	b := strings.Repeat("b\n", 5000) + "x\n"
+	actual := Unified("old", "new", a, b)
+	assert.Equal(t, 5000, strings.Count(actual, "\n-a"))
+	assert.Equal(t, 5000, strings.Count(actual, "\n+b"))
+}
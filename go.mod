@@ -3,6 +3,7 @@ module github.com/nekrassov01/backlog-utils
 go 1.26.2
 
 require (
+	github.com/BurntSushi/toml v1.6.0
 	github.com/jarcoal/httpmock v1.4.1
 	github.com/stretchr/testify v1.11.1
 	github.com/urfave/cli/v3 v3.8.0